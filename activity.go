@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// maxActivityEvents caps the activity log kept on the game state.
+	maxActivityEvents = 50
+
+	// activityFeedLines is how many of the latest events the expanded feed shows.
+	activityFeedLines = 8
+)
+
+// activityEvent is a single entry of the activity feed, like "alice joined".
+type activityEvent struct {
+	at   time.Time
+	text string
+}
+
+// logActivity records an event in the activity feed shown to the Scrum Master.
+// It may be called with or without state.mu held, as votes only hold it for
+// reading.
+func logActivity(format string, args ...any) {
+	event := activityEvent{at: time.Now(), text: fmt.Sprintf(format, args...)}
+
+	state.activityMu.Lock()
+	state.activity = appendBounded(state.activity, event, maxActivityEvents)
+	state.activityMu.Unlock()
+}
+
+// activityView renders the activity feed. Collapsed, only the latest event is
+// shown; expanded, the latest activityFeedLines events are shown. It returns an
+// empty string when nothing happened yet.
+func activityView(expanded bool) string {
+	state.activityMu.Lock()
+	defer state.activityMu.Unlock()
+
+	if len(state.activity) == 0 {
+		return ""
+	}
+
+	lines := 1
+	if expanded {
+		lines = activityFeedLines
+	}
+
+	var s strings.Builder
+	s.WriteString(t("activity.title") + "\n")
+	for _, event := range state.activity[max(len(state.activity)-lines, 0):] {
+		fmt.Fprintf(&s, "%s %s\n", event.at.Format("15:04:05"), event.text)
+	}
+	s.WriteString("\n")
+	return s.String()
+}
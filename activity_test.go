@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// activityTexts returns the texts of the logged activity events.
+func activityTexts() []string {
+	state.activityMu.Lock()
+	defer state.activityMu.Unlock()
+
+	texts := make([]string, 0, len(state.activity))
+	for _, event := range state.activity {
+		texts = append(texts, event.text)
+	}
+	return texts
+}
+
+// TestActivityJoinVoteLeave tests the events logged when players join, vote, and leave
+func TestActivityJoinVoteLeave(t *testing.T) {
+	state = newGameState()
+
+	addPlayer("alice", nil)
+	addPlayer("bob", nil)
+	castVote("carol", "5")
+	castVote("alice", "5")
+	state.mu.Lock()
+	removePlayer(playerKey("bob"))
+	removePlayer(playerKey("dave"))
+	state.mu.Unlock()
+
+	want := []string{"alice joined", "bob joined", "alice voted", "bob left"}
+	if got := activityTexts(); !slices.Equal(got, want) {
+		t.Errorf("activity = %v, want %v", got, want)
+	}
+}
+
+// TestActivityReconnect tests the events logged when a player loses their
+// connection and reconnects
+func TestActivityReconnect(t *testing.T) {
+	state = newGameState()
+	key := newTestPublicKey(t)
+	session := &stubSession{key: key}
+	addPlayer("alice", session)
+
+	state.mu.Lock()
+	markOffline(session)
+	state.mu.Unlock()
+	addPlayer("alice", &stubSession{key: key})
+
+	want := []string{"alice joined", "alice disconnected", "alice reconnected"}
+	if got := activityTexts(); !slices.Equal(got, want) {
+		t.Errorf("activity = %v, want %v", got, want)
+	}
+}
+
+// TestActivityView tests the collapsed and expanded activity feed and its cap
+func TestActivityView(t *testing.T) {
+	state = newGameState()
+	if got := activityView(true); got != "" {
+		t.Errorf("activityView() without events = %q, want empty", got)
+	}
+
+	for i := 0; i < maxActivityEvents+5; i++ {
+		logActivity("event %d", i)
+	}
+	if n := len(activityTexts()); n != maxActivityEvents {
+		t.Errorf("activity length = %d, want %d", n, maxActivityEvents)
+	}
+
+	last := fmt.Sprintf("event %d\n", maxActivityEvents+4)
+	collapsed := activityView(false)
+	if strings.Count(collapsed, "event") != 1 || !strings.Contains(collapsed, last) {
+		t.Errorf("collapsed feed should show only the latest event\nGot: %s", collapsed)
+	}
+	expanded := activityView(true)
+	if strings.Count(expanded, "event") != activityFeedLines || !strings.Contains(expanded, last) {
+		t.Errorf("expanded feed should show the latest %d events\nGot: %s", activityFeedLines, expanded)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// apiTimer describes the running timer in the JSON state API.
+type apiTimer struct {
+	Kind             string `json:"kind"`
+	RemainingSeconds int    `json:"remaining_seconds"`
+}
+
+// apiStory describes the story of the round in the JSON state API.
+type apiStory struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+}
+
+// apiState is the read-only snapshot of the game returned by GET /state.
+// Player points are only included once the votes are revealed.
+type apiState struct {
+	Round    int         `json:"round"`
+	Attempt  int         `json:"attempt"`
+	Revealed bool        `json:"revealed"`
+	Story    *apiStory   `json:"story,omitempty"`
+	Timer    *apiTimer   `json:"timer,omitempty"`
+	Players  []webPlayer `json:"players"`
+}
+
+// currentAPIState builds the state API snapshot under the read lock.
+func currentAPIState() apiState {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	s := apiState{
+		Round:    state.round,
+		Attempt:  state.attempt,
+		Revealed: resultsShared(time.Now()),
+	}
+
+	if story, ok := currentStory(); ok {
+		s.Story = &apiStory{Key: story.ID, Title: story.Title}
+	}
+
+	if !state.timerEnd.IsZero() {
+		kind := "voting"
+		if state.timerKind == discussionTimer {
+			kind = "discussion"
+		}
+		remaining := max(time.Until(state.timerEnd), 0)
+		s.Timer = &apiTimer{
+			Kind:             kind,
+			RemainingSeconds: int(remaining.Round(time.Second).Seconds()),
+		}
+	}
+
+	s.Players = publicPlayers()
+
+	return s
+}
+
+// publicPlayers lists the players sorted by name as exposed to browsers and
+// integrations, hiding their points until the votes are revealed. Callers must
+// hold state.mu.
+func publicPlayers() []webPlayer {
+	keys := sortedPlayerKeys()
+	players := make([]webPlayer, 0, len(keys))
+	for _, key := range keys {
+		player := state.players[key]
+		vote := player.vote()
+		p := webPlayer{Name: player.name, Voted: vote.voted()}
+		if resultsShared(time.Now()) {
+			p.Points = vote.points
+		}
+		players = append(players, p)
+	}
+	return players
+}
+
+// serveState handles GET /state, returning the current game as JSON.
+func serveState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(currentAPIState())
+}
+
+// serveResults handles GET /results.md, returning the revealed round as a
+// Markdown table. Before the reveal it responds with 409 Conflict.
+func serveResults(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	state.mu.RLock()
+	revealed := resultsShared(time.Now())
+	state.mu.RUnlock()
+	if !revealed {
+		http.Error(w, "votes are not revealed yet", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	io.WriteString(w, formatMarkdown(currentRoundResult()))
+}
+
+// newAPIHandler returns the HTTP handler of the read-only state API, serving
+// the game as JSON on /state and the revealed round as Markdown on /results.md.
+func newAPIHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", serveState)
+	mux.HandleFunc("/results.md", serveResults)
+	return mux
+}
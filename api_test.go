@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestServeStateHidesVotes tests that the state API omits points before reveal
+func TestServeStateHidesVotes(t *testing.T) {
+	state.mu.Lock()
+	setTestPlayers(map[string]*playerState{
+		"alice": {name: "alice", points: "5", selected: true},
+		"bob":   {name: "bob"},
+	})
+	state.masterRevealed = false
+	state.playersRevealed = false
+	state.backlog = []Story{{ID: "SD-1", Title: "Login page"}}
+	state.storyIndex = 0
+	state.mu.Unlock()
+	defer func() {
+		state.mu.Lock()
+		state.backlog = nil
+		state.mu.Unlock()
+	}()
+
+	rec := httptest.NewRecorder()
+	serveState(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /state status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if strings.Contains(rec.Body.String(), "points") {
+		t.Errorf("GET /state before reveal exposes points: %s", rec.Body.String())
+	}
+
+	var got apiState
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("GET /state returned invalid JSON: %v", err)
+	}
+	if len(got.Players) != 2 || !got.Players[0].Voted || got.Players[1].Voted {
+		t.Errorf("GET /state players = %+v, want alice voted and bob waiting", got.Players)
+	}
+	if got.Story == nil || got.Story.Key != "SD-1" || got.Story.Title != "Login page" {
+		t.Errorf("GET /state story = %+v, want SD-1 Login page", got.Story)
+	}
+
+	state.mu.Lock()
+	state.masterRevealed = true
+	state.playersRevealed = true
+	state.mu.Unlock()
+
+	rec = httptest.NewRecorder()
+	serveState(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("GET /state returned invalid JSON: %v", err)
+	}
+	if got.Players[0].Points != "5" {
+		t.Errorf("GET /state after reveal alice points = %q, want 5", got.Players[0].Points)
+	}
+}
+
+// TestServeStateMethod tests that the state API is read-only
+func TestServeStateMethod(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serveState(rec, httptest.NewRequest(http.MethodPost, "/state", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /state status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+// TestServeResults tests that the Markdown results are only served after reveal
+func TestServeResults(t *testing.T) {
+	state.mu.Lock()
+	setTestPlayers(map[string]*playerState{
+		"alice": {name: "alice", points: "5", selected: true},
+	})
+	state.masterRevealed = false
+	state.playersRevealed = false
+	state.mu.Unlock()
+
+	rec := httptest.NewRecorder()
+	serveResults(rec, httptest.NewRequest(http.MethodGet, "/results.md", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("GET /results.md before reveal status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	state.mu.Lock()
+	state.masterRevealed = true
+	state.playersRevealed = true
+	state.mu.Unlock()
+
+	rec = httptest.NewRecorder()
+	serveResults(rec, httptest.NewRequest(http.MethodGet, "/results.md", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /results.md after reveal status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !strings.Contains(rec.Body.String(), "| alice | 5 |") {
+		t.Errorf("GET /results.md missing alice's vote:\n%s", rec.Body.String())
+	}
+}
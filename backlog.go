@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Story is an item of the backlog the team estimates, one per round. A story
+// the master deferred is marked as skipped until it's re-queued.
+type Story struct {
+	ID      string
+	Title   string
+	skipped bool
+}
+
+// httpClient sends HTTP requests for the backlog integrations. It's satisfied
+// by *http.Client and lets tests stub the network.
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// estimateWriter writes the agreed estimate of a story back to the tracker the
+// backlog was imported from.
+type estimateWriter interface {
+	writeEstimate(story Story, estimate string) error
+}
+
+// estimateWriters receive the estimate of the current story when its votes
+// are revealed. They are set up in main() from the integration flags.
+var estimateWriters []estimateWriter
+
+// setBacklog replaces the backlog and starts at its first story.
+func setBacklog(stories []Story) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.backlog = stories
+	state.storyIndex = 0
+}
+
+// currentStory returns the story estimated in the current round, or false
+// without a backlog or once it's done. Callers must hold state.mu.
+func currentStory() (Story, bool) {
+	if state.storyIndex < 0 || state.storyIndex >= len(state.backlog) {
+		return Story{}, false
+	}
+	return state.backlog[state.storyIndex], true
+}
+
+// storyView renders the story of the current round, or an empty string
+// without a backlog. Callers must hold state.mu.
+func storyView() string {
+	if len(state.backlog) == 0 {
+		return ""
+	}
+	story, ok := currentStory()
+	if !ok {
+		if skipped := skippedStories(); skipped > 0 {
+			return t("story.completeSkipped", skipped) + "\n\n"
+		}
+		return t("story.complete") + "\n\n"
+	}
+	return fmt.Sprintf("📝 %s: %s\n\n", story.ID, story.Title)
+}
+
+// skippedStories returns how many stories of the backlog are skipped and not
+// re-queued yet. Callers must hold state.mu.
+func skippedStories() int {
+	skipped := 0
+	for _, story := range state.backlog {
+		if story.skipped {
+			skipped++
+		}
+	}
+	return skipped
+}
+
+// skipStory defers the current story without an estimate: it's marked as
+// skipped, recorded as such in the history, and the next round moves on to the
+// next story. It reports false without a current story.
+func skipStory() bool {
+	state.mu.Lock()
+	story, ok := currentStory()
+	if ok {
+		state.backlog[state.storyIndex].skipped = true
+		recordSkip(story, time.Now())
+		logActivity("%s skipped", story.ID)
+	}
+	state.mu.Unlock()
+
+	if ok {
+		nextRound()
+	}
+	return ok
+}
+
+// requeueSkipped appends the skipped stories to the end of the backlog again,
+// so they're estimated after the others. It reports how many were re-queued.
+func requeueSkipped() int {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	var requeued []Story
+	for i, story := range state.backlog {
+		if story.skipped {
+			state.backlog[i].skipped = false
+			story.skipped = false
+			requeued = append(requeued, story)
+		}
+	}
+	state.backlog = append(state.backlog, requeued...)
+	return len(requeued)
+}
+
+// roundEstimate returns the card nearest to the average of the current votes,
+// or an empty string without numeric votes. Callers must hold state.mu.
+func roundEstimate() string {
+	var points []string
+	for _, player := range state.players {
+		if vote := player.vote(); vote.selected {
+			points = append(points, vote.points)
+		}
+	}
+	avg, _, _ := calculateStatistics(points)
+	if avg == 0 {
+		return ""
+	}
+	return nearestCard(avg, activeDeck())
+}
+
+// publishEstimate writes the estimate of a story to every estimate writer,
+// logging failures. It does network calls, so it runs without holding
+// state.mu.
+func publishEstimate(story Story, estimate string) {
+	for _, w := range estimateWriters {
+		if err := w.writeEstimate(story, estimate); err != nil {
+			log.Error("Could not write estimate", "story", story.ID, "estimate", estimate, "error", err)
+			continue
+		}
+		log.Info("Wrote estimate", "story", story.ID, "estimate", estimate)
+	}
+}
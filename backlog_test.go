@@ -0,0 +1,132 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recordingWriter is an estimate writer remembering the estimates it got.
+type recordingWriter struct {
+	written chan string
+}
+
+// writeEstimate records the story and estimate.
+func (w *recordingWriter) writeEstimate(story Story, estimate string) error {
+	w.written <- story.ID + "=" + estimate
+	return nil
+}
+
+// TestBacklogFollowsRounds tests that each new round moves to the next story
+func TestBacklogFollowsRounds(t *testing.T) {
+	state = newGameState()
+	setBacklog([]Story{{ID: "PROJ-1", Title: "Fix login"}, {ID: "PROJ-2", Title: "Add dark mode"}})
+
+	if got := storyView(); got != "📝 PROJ-1: Fix login\n\n" {
+		t.Errorf("storyView() = %q, want PROJ-1", got)
+	}
+	nextRound()
+	if got := storyView(); !strings.Contains(got, "PROJ-2") {
+		t.Errorf("storyView() after nextRound() = %q, want PROJ-2", got)
+	}
+	nextRound()
+	nextRound()
+	if got := storyView(); got != "📝 Backlog complete\n\n" {
+		t.Errorf("storyView() after the backlog = %q, want complete", got)
+	}
+}
+
+// TestRevealWritesEstimate tests that revealing writes the estimate of the
+// current story once per attempt
+func TestRevealWritesEstimate(t *testing.T) {
+	defer func(writers []estimateWriter) { estimateWriters = writers }(estimateWriters)
+
+	writer := &recordingWriter{written: make(chan string, 2)}
+	estimateWriters = []estimateWriter{writer}
+
+	state = newGameState()
+	setBacklog([]Story{{ID: "PROJ-1", Title: "Fix login"}})
+	addPlayer("alice", nil)
+	addPlayer("bob", nil)
+	castVote("alice", "3")
+	castVote("bob", "8")
+
+	revealVotes()
+	revealVotes()
+
+	select {
+	case got := <-writer.written:
+		if got != "PROJ-1=5" {
+			t.Errorf("written estimate = %s, want PROJ-1=5", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no estimate written on reveal")
+	}
+	select {
+	case got := <-writer.written:
+		t.Errorf("estimate written twice for one attempt: %s", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestSkipStory tests that skipping records the story as skipped without an
+// estimate, advances to the next story, and re-queues skipped stories at the
+// end of the backlog
+func TestSkipStory(t *testing.T) {
+	defer func(writers []estimateWriter) { estimateWriters = writers }(estimateWriters)
+	writer := &recordingWriter{written: make(chan string, 1)}
+	estimateWriters = []estimateWriter{writer}
+
+	state = newGameState()
+	setBacklog([]Story{{ID: "PROJ-1", Title: "Fix login"}, {ID: "PROJ-2", Title: "Add dark mode"}})
+	addPlayer("alice", nil)
+	castVote("alice", "3")
+
+	var model tea.Model = newMasterView()
+	skip := func() {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(".")})
+	}
+
+	skip()
+	state.mu.RLock()
+	view := storyView()
+	history := slices.Clone(state.history)
+	round, selected := state.round, state.players["alice"].selected
+	state.mu.RUnlock()
+	if !strings.Contains(view, "PROJ-2") || round != 2 || selected {
+		t.Errorf("after skip: story %q round %d vote kept %v, want PROJ-2 in round 2 without votes", view, round, selected)
+	}
+	if len(history) != 1 || !history[0].skipped || history[0].story != "PROJ-1" {
+		t.Errorf("history after skip = %+v, want PROJ-1 skipped", history)
+	}
+	select {
+	case got := <-writer.written:
+		t.Errorf("skipping wrote estimate %s, want none", got)
+	default:
+	}
+
+	// Finish the backlog, then re-queue the skipped story
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	state.mu.RLock()
+	view = storyView()
+	state.mu.RUnlock()
+	if view != "📝 Backlog complete, 1 skipped (press . to re-queue)\n\n" {
+		t.Errorf("storyView() with a skipped story = %q, want complete with 1 skipped", view)
+	}
+
+	skip()
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if view := storyView(); view != "📝 PROJ-1: Fix login\n\n" {
+		t.Errorf("storyView() after re-queue = %q, want PROJ-1", view)
+	}
+	if n := skippedStories(); n != 0 {
+		t.Errorf("skippedStories() after re-queue = %d, want 0", n)
+	}
+	if len(state.backlog) != 3 {
+		t.Errorf("backlog after re-queue has %d stories, want 3", len(state.backlog))
+	}
+}
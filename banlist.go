@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// banList holds the SSH key fingerprints and IP ranges refused at auth time.
+// It is loaded from the -banlist file and reloaded on SIGHUP.
+type banList struct {
+	mu           sync.RWMutex
+	fingerprints map[string]bool
+	networks     []*net.IPNet
+}
+
+// bans is the active ban list. It is empty unless -banlist is given.
+var bans = &banList{}
+
+// parseBanList reads a ban list with one entry per line: either a SHA256 key
+// fingerprint as printed by ssh-keygen -l, a CIDR range, or a single IP
+// address. Empty lines and lines starting with # are ignored.
+func parseBanList(r io.Reader) (map[string]bool, []*net.IPNet, error) {
+	fingerprints := make(map[string]bool)
+	var networks []*net.IPNet
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "SHA256:"):
+			fingerprints[line] = true
+		case strings.Contains(line, "/"):
+			_, network, err := net.ParseCIDR(line)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: invalid CIDR range %q", lineNo, line)
+			}
+			networks = append(networks, network)
+		default:
+			ip := net.ParseIP(line)
+			if ip == nil {
+				return nil, nil, fmt.Errorf("line %d: expected a key fingerprint, CIDR range, or IP address, got %q", lineNo, line)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return fingerprints, networks, nil
+}
+
+// load replaces the ban list with the entries of the file at path. The current
+// list is kept when the file can't be read or parsed.
+func (b *banList) load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open ban list: %w", err)
+	}
+	defer f.Close()
+
+	fingerprints, networks, err := parseBanList(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse ban list %s: %w", path, err)
+	}
+
+	b.mu.Lock()
+	b.fingerprints = fingerprints
+	b.networks = networks
+	b.mu.Unlock()
+
+	return nil
+}
+
+// bannedKey reports whether the fingerprint of key is banned.
+func (b *banList) bannedKey(key gossh.PublicKey) bool {
+	if key == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.fingerprints[gossh.FingerprintSHA256(key)]
+}
+
+// bannedAddr reports whether the IP of addr falls in a banned range.
+func (b *banList) bannedAddr(addr net.Addr) bool {
+	if addr == nil {
+		return false
+	}
+	host := addr.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, network := range b.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// banMiddleware refuses sessions whose key or address was banned after they
+// authenticated, like by a ban list reloaded on SIGHUP, with a generic denial.
+// It must run before the Bubble Tea middleware, so banned players never reach
+// the game.
+func banMiddleware() wish.Middleware {
+	return func(h ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			if bans.bannedAddr(s.RemoteAddr()) || bans.bannedKey(s.PublicKey()) {
+				log.Warn("Refused banned connection", "remote", s.RemoteAddr(), "user", s.User())
+				wish.Fatalln(s, "Access denied")
+				return
+			}
+			h(s)
+		}
+	}
+}
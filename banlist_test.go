@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// newTestPublicKey generates a random ed25519 SSH public key
+func newTestPublicKey(t *testing.T) gossh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// TestBanListFingerprint tests that banned key fingerprints are matched
+func TestBanListFingerprint(t *testing.T) {
+	banned := newTestPublicKey(t)
+	allowed := newTestPublicKey(t)
+
+	path := filepath.Join(t.TempDir(), "banned.txt")
+	content := "# banned keys\n" + gossh.FingerprintSHA256(banned) + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &banList{}
+	if err := b.load(path); err != nil {
+		t.Fatalf("load() err = %v", err)
+	}
+
+	if !b.bannedKey(banned) {
+		t.Errorf("bannedKey() for banned key = false, want true")
+	}
+	if b.bannedKey(allowed) {
+		t.Errorf("bannedKey() for allowed key = true, want false")
+	}
+	if b.bannedKey(nil) {
+		t.Errorf("bannedKey(nil) = true, want false")
+	}
+}
+
+// TestBanListCIDR tests that banned IP ranges and addresses are matched
+func TestBanListCIDR(t *testing.T) {
+	fingerprints, networks, err := parseBanList(strings.NewReader("10.0.0.0/8\n192.168.1.5\n2001:db8::/32\n"))
+	if err != nil {
+		t.Fatalf("parseBanList() err = %v", err)
+	}
+	b := &banList{fingerprints: fingerprints, networks: networks}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"11.0.0.1", false},
+		{"192.168.1.5", true},
+		{"192.168.1.6", false},
+		{"2001:db8::1", true},
+		{"2001:db9::1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			addr := &net.TCPAddr{IP: net.ParseIP(tt.ip), Port: 22}
+			if got := b.bannedAddr(addr); got != tt.want {
+				t.Errorf("bannedAddr(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseBanListInvalid tests that invalid entries are reported
+func TestParseBanListInvalid(t *testing.T) {
+	for _, content := range []string{"not-an-ip\n", "10.0.0.0/99\n"} {
+		if _, _, err := parseBanList(strings.NewReader(content)); err == nil {
+			t.Errorf("parseBanList(%q) err = nil, want error", content)
+		}
+	}
+}
+
+// bannedSession is a stub SSH session from an address, recording what's
+// written to its standard error and its exit code. Other methods aren't
+// implemented.
+type bannedSession struct {
+	ssh.Session
+	addr   net.Addr
+	stderr bytes.Buffer
+	code   int
+}
+
+// PublicKey returns no key, like a keyless session.
+func (s *bannedSession) PublicKey() ssh.PublicKey { return nil }
+
+// RemoteAddr returns the address the session connects from.
+func (s *bannedSession) RemoteAddr() net.Addr { return s.addr }
+
+// User returns the user of the session.
+func (s *bannedSession) User() string { return "mallory" }
+
+// Stderr returns the recorded standard error.
+func (s *bannedSession) Stderr() io.ReadWriter { return &s.stderr }
+
+// Exit records the exit code.
+func (s *bannedSession) Exit(code int) error {
+	s.code = code
+	return nil
+}
+
+// Close does nothing.
+func (s *bannedSession) Close() error { return nil }
+
+// TestBanMiddleware tests that sessions banned after authenticating are
+// refused before reaching the game
+func TestBanMiddleware(t *testing.T) {
+	defer func(b *banList) { bans = b }(bans)
+	fingerprints, networks, err := parseBanList(strings.NewReader("10.0.0.0/8\n"))
+	if err != nil {
+		t.Fatalf("parseBanList() err = %v", err)
+	}
+	bans = &banList{fingerprints: fingerprints, networks: networks}
+
+	tests := []struct {
+		ip         string
+		wantServed bool
+	}{
+		{"10.1.2.3", false},
+		{"192.168.1.5", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			s := &bannedSession{addr: &net.TCPAddr{IP: net.ParseIP(tt.ip), Port: 22}}
+			served := false
+			banMiddleware()(func(ssh.Session) { served = true })(s)
+			if served != tt.wantServed {
+				t.Errorf("served = %v, want %v", served, tt.wantServed)
+			}
+			if denied := strings.Contains(s.stderr.String(), "Access denied"); denied == tt.wantServed || (s.code == 1) == tt.wantServed {
+				t.Errorf("stderr = %q, exit code %d, want a denial %v", s.stderr.String(), s.code, !tt.wantServed)
+			}
+		})
+	}
+}
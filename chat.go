@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxChatMessages caps the chat log kept on the game state.
+	maxChatMessages = 50
+
+	// chatPaneLines is how many of the latest chat messages are shown.
+	chatPaneLines = 5
+)
+
+// chatMessage is a single line of the chat between participants.
+type chatMessage struct {
+	name string
+	text string
+}
+
+// appendBounded appends an entry to a log, dropping the oldest entries beyond
+// max so the log never grows unbounded.
+func appendBounded[T any](log []T, entry T, max int) []T {
+	log = append(log, entry)
+	if len(log) > max {
+		// Copy to a new slice so the dropped entries can be collected
+		log = append([]T(nil), log[len(log)-max:]...)
+	}
+	return log
+}
+
+// addChatMessage posts a chat message from the named participant.
+func addChatMessage(name, text string) {
+	state.mu.Lock()
+	state.chat = appendBounded(state.chat, chatMessage{name: name, text: text}, maxChatMessages)
+	state.mu.Unlock()
+}
+
+// chatView renders the latest chat messages as a small pane, or an empty
+// string when nobody has chatted yet. Callers must hold state.mu.
+func chatView() string {
+	if len(state.chat) == 0 {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteString(t("chat.title") + "\n")
+	for _, msg := range state.chat[max(len(state.chat)-chatPaneLines, 0):] {
+		fmt.Fprintf(&s, "%s: %s\n", msg.name, msg.text)
+	}
+	s.WriteString("\n")
+	return s.String()
+}
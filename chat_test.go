@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestAppendBoundedTrimming tests that the chat log is capped and keeps the latest messages
+func TestAppendBoundedTrimming(t *testing.T) {
+	var log []chatMessage
+	for i := 0; i < 8; i++ {
+		log = appendBounded(log, chatMessage{name: "alice", text: fmt.Sprintf("message %d", i)}, 5)
+	}
+
+	if len(log) != 5 {
+		t.Fatalf("chat log length = %d, want 5", len(log))
+	}
+	if log[0].text != "message 3" || log[4].text != "message 7" {
+		t.Errorf("chat log = %v, want messages 3 to 7", log)
+	}
+}
+
+// TestChatView tests that only the latest messages are rendered
+func TestChatView(t *testing.T) {
+	state = newGameState()
+	if got := chatView(); got != "" {
+		t.Errorf("chatView() without messages = %q, want empty", got)
+	}
+
+	for i := 0; i < chatPaneLines+2; i++ {
+		addChatMessage("bob", fmt.Sprintf("line %d", i))
+	}
+
+	got := chatView()
+	if strings.Contains(got, "bob: line 1\n") {
+		t.Errorf("chatView() shows old message\nGot: %s", got)
+	}
+	if !strings.Contains(got, fmt.Sprintf("bob: line %d", chatPaneLines+1)) {
+		t.Errorf("chatView() missing latest message\nGot: %s", got)
+	}
+}
+
+// TestPlayerChat tests sending a chat message from the player view
+func TestPlayerChat(t *testing.T) {
+	state = newGameState()
+	model, _ := initPlayerView("alice", nil)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("coffee?")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if len(state.chat) != 1 || state.chat[0].name != "alice" || state.chat[0].text != "coffee?" {
+		t.Errorf("chat log = %v, want alice: coffee?", state.chat)
+	}
+	if player := state.players["alice"]; player.reaction != "" || player.selected {
+		t.Errorf("typing ? in chat set a reaction or voted")
+	}
+}
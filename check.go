@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// configCheck is a single validation of the -check self-test.
+type configCheck struct {
+	name string
+	run  func() error
+}
+
+// configChecks returns the checks of the -check self-test for the
+// configuration: the host keys, the authorized keys, the deck, and the
+// configured files.
+func configChecks(cfg Config) []configCheck {
+	var checks []configCheck
+	for _, path := range cfg.HostKeys {
+		checks = append(checks, configCheck{"host key " + path, func() error { return checkHostKey(path) }})
+	}
+	checks = append(checks,
+		configCheck{"authorized keys " + cfg.KeysPath, func() error { return checkAuthorizedKeys(cfg.KeysPath) }},
+		configCheck{"deck", func() error { return checkDeck(cfg.Deck) }},
+	)
+	if cfg.MasterNamesPath != "" {
+		checks = append(checks, configCheck{"master names " + cfg.MasterNamesPath, func() error { return checkMasterNames(cfg.MasterNamesPath) }})
+	}
+	if cfg.FacilitatorsPath != "" {
+		checks = append(checks, configCheck{"facilitators " + cfg.FacilitatorsPath, func() error { return checkFacilitators(cfg.FacilitatorsPath) }})
+	}
+	if cfg.BanlistPath != "" {
+		checks = append(checks, configCheck{"ban list " + cfg.BanlistPath, func() error { return checkBanList(cfg.BanlistPath) }})
+	}
+	if cfg.StateFile != "" {
+		checks = append(checks, configCheck{"state file " + cfg.StateFile, func() error { return checkStateFile(cfg.StateFile) }})
+	}
+	return checks
+}
+
+// runChecks runs the checks in order and writes a report line per check to
+// w. It reports whether all checks passed.
+func runChecks(checks []configCheck, w io.Writer) bool {
+	ok := true
+	for _, c := range checks {
+		if err := c.run(); err != nil {
+			fmt.Fprintf(w, "FAIL %s: %v\n", c.name, err)
+			ok = false
+			continue
+		}
+		fmt.Fprintf(w, "ok   %s\n", c.name)
+	}
+	return ok
+}
+
+// checkHostKey checks that the host key exists and is a parseable private key.
+// Unlike on startup, a missing key is a problem, as it would be generated on
+// the first start of each container.
+func checkHostKey(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := gossh.ParsePrivateKey(data); err != nil {
+		return fmt.Errorf("failed to parse host key %s: %w", path, err)
+	}
+	return nil
+}
+
+// checkAuthorizedKeys checks that every entry of the authorized keys file of
+// the Scrum Masters parses and that there's at least one.
+func checkAuthorizedKeys(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	keys := 0
+	for lineNo, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, _, _, _, err := gossh.ParseAuthorizedKey([]byte(line)); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo+1, err)
+		}
+		keys++
+	}
+	if keys == 0 {
+		return fmt.Errorf("no keys in %s", path)
+	}
+	return nil
+}
+
+// checkDeck checks that the deck has cards and no empty or duplicate ones.
+func checkDeck(deck []string) error {
+	if len(deck) == 0 {
+		return errors.New("deck is empty")
+	}
+	for i, card := range deck {
+		if strings.TrimSpace(card) == "" {
+			return fmt.Errorf("card %d is empty", i+1)
+		}
+		if slices.Index(deck, card) != i {
+			return fmt.Errorf("card %q is in the deck twice", card)
+		}
+	}
+	return nil
+}
+
+// checkStateFile checks that an existing state file parses. A missing file is
+// fine, as it's created on the first snapshot.
+func checkStateFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var snapshot gameSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return nil
+}
+
+// checkBanList checks that the ban list file loads.
+func checkBanList(path string) error {
+	return (&banList{}).load(path)
+}
+
+// checkFacilitators checks that the facilitators file parses.
+func checkFacilitators(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = parseFacilitators(f)
+	return err
+}
+
+// checkMasterNames checks that the master names file parses.
+func checkMasterNames(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = parseMasterNames(f)
+	return err
+}
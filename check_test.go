@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// writeTestFile writes content to name in a temporary directory and returns its path
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestCheckHostKey tests validating parseable, broken, and missing host keys
+func TestCheckHostKey(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := gossh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkHostKey(writeTestFile(t, "host", string(pem.EncodeToMemory(block)))); err != nil {
+		t.Errorf("checkHostKey() of valid key err = %v", err)
+	}
+	if err := checkHostKey(writeTestFile(t, "host", "not a key")); err == nil {
+		t.Errorf("checkHostKey() of broken key err = nil, want error")
+	}
+	if err := checkHostKey(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("checkHostKey() of missing key err = nil, want error")
+	}
+}
+
+// TestCheckAuthorizedKeys tests validating the authorized keys file
+func TestCheckAuthorizedKeys(t *testing.T) {
+	key := string(gossh.MarshalAuthorizedKey(newTestPublicKey(t)))
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid keys", "# masters\n" + key + key, false},
+		{"broken entry", key + "ssh-ed25519 garbage\n", true},
+		{"no keys", "# nobody yet\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAuthorizedKeys(writeTestFile(t, "showdown_keys", tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkAuthorizedKeys() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCheckDeck tests validating the deck of cards
+func TestCheckDeck(t *testing.T) {
+	tests := []struct {
+		name    string
+		deck    []string
+		wantErr bool
+	}{
+		{"default deck", pointOptions, false},
+		{"empty deck", nil, true},
+		{"empty card", []string{"1", " "}, true},
+		{"duplicate card", []string{"1", "2", "1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkDeck(tt.deck); (err != nil) != tt.wantErr {
+				t.Errorf("checkDeck(%v) err = %v, wantErr %v", tt.deck, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCheckStateFile tests validating existing, broken, and missing state files
+func TestCheckStateFile(t *testing.T) {
+	if err := checkStateFile(writeTestFile(t, "state.json", `{"round": 2, "players": []}`)); err != nil {
+		t.Errorf("checkStateFile() of valid file err = %v", err)
+	}
+	if err := checkStateFile(writeTestFile(t, "state.json", `{"round":`)); err == nil {
+		t.Errorf("checkStateFile() of broken file err = nil, want error")
+	}
+	if err := checkStateFile(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("checkStateFile() of missing file err = %v, want nil", err)
+	}
+}
+
+// TestRunChecks tests the report and result of the self-test
+func TestRunChecks(t *testing.T) {
+	var out bytes.Buffer
+	ok := runChecks([]configCheck{
+		{"deck", func() error { return nil }},
+		{"host key", func() error { return errors.New("no such file") }},
+	}, &out)
+
+	if ok {
+		t.Errorf("runChecks() = true with a failing check, want false")
+	}
+	want := "ok   deck\nFAIL host key: no such file\n"
+	if out.String() != want {
+		t.Errorf("runChecks() report = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if !runChecks([]configCheck{{"deck", func() error { return nil }}}, &out) {
+		t.Errorf("runChecks() = false with passing checks, want true")
+	}
+	if strings.Contains(out.String(), "FAIL") {
+		t.Errorf("runChecks() report = %q, want no failures", out.String())
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// A sealed vote lets a player commit to a card without the server or the Scrum
+// Master learning it before the reveal: the player sends the SHA256 of
+// "<card>:<nonce>" with "commit <hash>", and once the votes are revealed opens
+// it with "open <card> <nonce>". Votes picked in the terminal view are typed on
+// the server, so only one-shot commands can seal them; the terminal view only
+// shows that a vote is sealed and how to open it.
+//
+// A sealed vote counts as voted, and the reveal event and estimate write-back
+// of a round wait until every sealed vote is opened or the round moves on.
+
+var (
+	errNoCommit       = errors.New("no sealed vote to open")
+	errCommitMismatch = errors.New("card and nonce don't match the sealed vote")
+	errNotRevealed    = errors.New("votes aren't revealed yet")
+)
+
+// commitHash returns the hex SHA256 commitment of a card and a nonce.
+func commitHash(card, nonce string) string {
+	sum := sha256.Sum256([]byte(card + ":" + nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyCommit reports whether card and nonce open the commitment.
+func verifyCommit(commit, card, nonce string) bool {
+	return subtle.ConstantTimeCompare([]byte(commit), []byte(commitHash(card, nonce))) == 1
+}
+
+// validCommit reports whether commit looks like a hex SHA256 hash.
+func validCommit(commit string) bool {
+	decoded, err := hex.DecodeString(commit)
+	return err == nil && len(decoded) == sha256.Size
+}
+
+// voted reports whether the player voted in the round, with a card or a sealed
+// vote.
+func (v playerVote) voted() bool {
+	return v.selected || v.committed
+}
+
+// sealedVotes reports whether any player has a sealed vote left to open.
+// Callers must hold state.mu.
+func sealedVotes() bool {
+	for _, player := range state.players {
+		if player.vote().committed {
+			return true
+		}
+	}
+	return false
+}
+
+// announceReveal emits the reveal event of the round and, with publish set,
+// writes its estimate back to the tracker the backlog came from, unless sealed
+// votes are left to open. Callers must hold state.mu for writing.
+func announceReveal(publish bool) {
+	state.revealPending = true
+	state.estimatePending = state.estimatePending || publish
+	if !sealedVotes() {
+		settleReveal()
+	}
+}
+
+// settleReveal emits the reveal event and writes back the estimate held back
+// by announceReveal, if any. Callers must hold state.mu for writing.
+func settleReveal() {
+	if !state.revealPending {
+		return
+	}
+	events.emit(revealEvent())
+	if state.estimatePending && len(estimateWriters) > 0 {
+		if story, ok := currentStory(); ok {
+			if estimate := roundEstimate(); estimate != "" {
+				go publishEstimate(story, estimate)
+			}
+		}
+	}
+	state.revealPending = false
+	state.estimatePending = false
+}
+
+// castCommit records the sealed vote of the named player, replacing any vote
+// of the round. It returns false once votes are revealed or if the player is
+// unknown.
+func castCommit(name, commit string) bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	if state.masterRevealed {
+		return false
+	}
+	player, exists := state.players[playerKey(name)]
+	if !exists {
+		return false
+	}
+	player.mu.Lock()
+	player.commit = commit
+	player.points = ""
+	player.selected = false
+	player.mu.Unlock()
+	logActivity("%s sealed a vote", player.name)
+	return true
+}
+
+// openCommit opens the sealed vote of the named player after the reveal and
+// records the card when it matches the commitment. Opening the last sealed
+// vote announces the reveal.
+func openCommit(name, card, nonce string) error {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.masterRevealed {
+		return errNotRevealed
+	}
+	player, exists := state.players[playerKey(name)]
+	if !exists || player.commit == "" {
+		return errNoCommit
+	}
+	if !verifyCommit(player.commit, card, nonce) {
+		return errCommitMismatch
+	}
+	player.commit = ""
+	player.points = card
+	player.selected = true
+	player.abstained = false
+	if player.votedAt.IsZero() {
+		player.votedAt = time.Now()
+	}
+	logActivity("%s opened their vote", player.name)
+	events.emit(event{Type: "vote", Round: state.round, Player: player.name, Points: card})
+	if !sealedVotes() {
+		settleReveal()
+	}
+	return nil
+}
+
+// commandCommit seals the vote of a one-shot command like
+// "ssh host commit <hash> --name alice".
+func commandCommit(s ssh.Session) {
+	name, args, err := parseNameArgs(s.Command(), s.User())
+	if err != nil || len(args) != 1 {
+		wish.Fatalln(s, "usage: commit <sha256 of card:nonce> [--name <name>]")
+		return
+	}
+	if !validCommit(args[0]) {
+		wish.Fatalf(s, "%q is not a hex SHA256 hash\n", args[0])
+		return
+	}
+	if err := checkJoin(name, s); err != nil {
+		wish.Fatalf(s, "Cannot join as %q: %v\n", name, err)
+		return
+	}
+	addPlayer(name, s)
+	if !castCommit(name, args[0]) {
+		wish.Fatalln(s, "Voting is closed")
+		return
+	}
+	fmt.Fprintf(s, "Sealed vote as %s, open it after the reveal\n", name)
+}
+
+// commandOpen opens the sealed vote of a one-shot command like
+// "ssh host open 5 <nonce> --name alice".
+func commandOpen(s ssh.Session) {
+	name, args, err := parseNameArgs(s.Command(), s.User())
+	if err != nil || len(args) != 2 {
+		wish.Fatalln(s, "usage: open <card> <nonce> [--name <name>]")
+		return
+	}
+	card, err := parseLineVote(args[0], currentDeck())
+	if err != nil {
+		wish.Fatalln(s, err)
+		return
+	}
+	if err := openCommit(name, card, args[1]); err != nil {
+		wish.Fatalln(s, err)
+		return
+	}
+	fmt.Fprintf(s, "Opened vote %s as %s\n", card, name)
+}
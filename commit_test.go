@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestVerifyCommit tests that only the committed card and nonce open a sealed
+// vote
+func TestVerifyCommit(t *testing.T) {
+	commit := commitHash("5", "s3cret")
+
+	tests := []struct {
+		name  string
+		card  string
+		nonce string
+		want  bool
+	}{
+		{"matching card and nonce", "5", "s3cret", true},
+		{"other card", "8", "s3cret", false},
+		{"other nonce", "5", "guess", false},
+		{"separator moved", "5:s3", "cret", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyCommit(commit, tt.card, tt.nonce); got != tt.want {
+				t.Errorf("verifyCommit(%q, %q) = %v, want %v", tt.card, tt.nonce, got, tt.want)
+			}
+		})
+	}
+
+	if !validCommit(commit) {
+		t.Errorf("validCommit(%q) = false, want true", commit)
+	}
+	for _, commit := range []string{"", "5", commit[:62], commit + "00", "zz" + commit[2:]} {
+		if validCommit(commit) {
+			t.Errorf("validCommit(%q) = true, want false", commit)
+		}
+	}
+}
+
+// TestOpenCommit tests that a sealed vote is hidden until the reveal and
+// counts once opened with the matching card and nonce
+func TestOpenCommit(t *testing.T) {
+	state = newGameState()
+	player, _ := initPlayerView("alice", nil)
+
+	if err := openCommit("alice", "5", "n"); !errors.Is(err, errNotRevealed) {
+		t.Errorf("openCommit() before the reveal = %v, want %v", err, errNotRevealed)
+	}
+	if !castCommit("alice", commitHash("5", "n")) {
+		t.Fatalf("castCommit() = false, want true")
+	}
+	state.mu.RLock()
+	vote := state.players["alice"].vote()
+	waiting := waitingOn()
+	state.mu.RUnlock()
+	if vote.selected || !vote.committed {
+		t.Errorf("vote after commit = selected %v committed %v, want sealed only", vote.selected, vote.committed)
+	}
+	if len(waiting) != 0 {
+		t.Errorf("waitingOn() = %v, want nobody", waiting)
+	}
+	if view := player.View(); !strings.Contains(view, "Vote sealed") {
+		t.Errorf("player View() doesn't show the sealed vote:\n%s", view)
+	}
+
+	revealVotes()
+	if castCommit("alice", commitHash("8", "n")) {
+		t.Errorf("castCommit() after the reveal = true, want false")
+	}
+	if err := openCommit("alice", "8", "n"); !errors.Is(err, errCommitMismatch) {
+		t.Errorf("openCommit() with another card = %v, want %v", err, errCommitMismatch)
+	}
+	if err := openCommit("alice", "5", "n"); err != nil {
+		t.Fatalf("openCommit() = %v, want nil", err)
+	}
+	state.mu.RLock()
+	vote = state.players["alice"].vote()
+	state.mu.RUnlock()
+	if !vote.selected || vote.points != "5" || vote.committed {
+		t.Errorf("vote after open = %+v, want 5", vote)
+	}
+	if err := openCommit("alice", "5", "n"); !errors.Is(err, errNoCommit) {
+		t.Errorf("openCommit() twice = %v, want %v", err, errNoCommit)
+	}
+}
+
+// TestSealedVoteCounts tests that a sealed vote counts as voted, isn't
+// abstained, and holds back the estimate and reveal event until it's opened
+func TestSealedVoteCounts(t *testing.T) {
+	defer func(writers []estimateWriter) { estimateWriters = writers }(estimateWriters)
+	writer := &recordingWriter{written: make(chan string, 1)}
+	estimateWriters = []estimateWriter{writer}
+	options.MinVoters = 2
+	defer func() { options.MinVoters = 0 }()
+
+	state = newGameState()
+	setBacklog([]Story{{ID: "PROJ-1", Title: "Fix login"}})
+	addPlayer("alice", nil)
+	addPlayer("bob", nil)
+	addPlayer("carol", nil)
+	castVote("alice", "3")
+	castCommit("bob", commitHash("8", "n"))
+
+	state.mu.RLock()
+	missing := missingVoters()
+	state.mu.RUnlock()
+	if missing != 0 {
+		t.Errorf("missingVoters() = %d, want 0 with a sealed vote", missing)
+	}
+
+	abstainSilent()
+	revealVotes()
+	state.mu.RLock()
+	bob, carol := state.players["bob"].vote(), state.players["carol"].vote()
+	pending := state.revealPending
+	state.mu.RUnlock()
+	if bob.abstained || !bob.committed || !carol.abstained {
+		t.Errorf("after abstaining: bob %+v, carol %+v, want only carol abstained", bob, carol)
+	}
+	if !pending {
+		t.Errorf("reveal announced before the sealed vote was opened")
+	}
+	select {
+	case got := <-writer.written:
+		t.Errorf("estimate %s written before the sealed vote was opened", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := openCommit("bob", "8", "n"); err != nil {
+		t.Fatalf("openCommit() = %v, want nil", err)
+	}
+	select {
+	case got := <-writer.written:
+		if got != "PROJ-1=5" {
+			t.Errorf("written estimate = %s, want PROJ-1=5 with the opened vote", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no estimate written once the sealed vote was opened")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// confidence is how sure a player is about their vote. Players tag it after
+// voting so uncertain estimates get discussed even when the numbers match.
+type confidence int
+
+const (
+	confidenceNone confidence = iota
+	confidenceLow
+	confidenceMedium
+	confidenceHigh
+)
+
+// String returns the label of the confidence level, or an empty string when
+// the vote isn't tagged.
+func (c confidence) String() string {
+	switch c {
+	case confidenceLow:
+		return "low"
+	case confidenceMedium:
+		return "med"
+	case confidenceHigh:
+		return "high"
+	}
+	return ""
+}
+
+// label returns the name of the confidence level in the language of the views.
+func (c confidence) label() string {
+	return t("confidence." + c.String())
+}
+
+// next returns the level after c, going from untagged through low, medium,
+// and high back to untagged.
+func (c confidence) next() confidence {
+	return (c + 1) % (confidenceHigh + 1)
+}
+
+// cycleConfidence moves the confidence of the player's vote to the next level.
+// It returns the new level and false when the player hasn't voted or voting is
+// closed.
+func cycleConfidence(name string) (confidence, bool) {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	if state.masterRevealed {
+		return confidenceNone, false
+	}
+	player, exists := state.players[playerKey(name)]
+	if !exists {
+		return confidenceNone, false
+	}
+
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	if !player.selected {
+		return confidenceNone, false
+	}
+	player.confidence = player.confidence.next()
+	return player.confidence, true
+}
+
+// confidenceSummary renders how many votes were tagged with each confidence
+// level, like "🎯 Confidence: 2 high, 1 low", from high to low. It returns an
+// empty string when no vote was tagged.
+func confidenceSummary(levels []confidence) string {
+	counts := make(map[confidence]int)
+	for _, level := range levels {
+		if level != confidenceNone {
+			counts[level]++
+		}
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, level := range []confidence{confidenceHigh, confidenceMedium, confidenceLow} {
+		if counts[level] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[level], level.label()))
+		}
+	}
+	return t("confidence.title", strings.Join(parts, ", ")) + "\n"
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestConfidenceStorage tests tagging a vote with a confidence level
+func TestConfidenceStorage(t *testing.T) {
+	state = newGameState()
+	model, _ := initPlayerView("alice", nil)
+	press := func(key string) {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	confidenceOf := func() confidence {
+		state.mu.RLock()
+		defer state.mu.RUnlock()
+		return state.players["alice"].vote().confidence
+	}
+
+	// Nothing to be confident about before voting
+	if _, ok := cycleConfidence("alice"); ok {
+		t.Errorf("cycleConfidence() before voting ok = true, want false")
+	}
+
+	press("5")
+	for _, want := range []confidence{confidenceLow, confidenceMedium, confidenceHigh, confidenceNone, confidenceLow} {
+		press("c")
+		if got := confidenceOf(); got != want {
+			t.Fatalf("confidence after c = %q, want %q", got, want)
+		}
+	}
+	if view := model.View(); !strings.Contains(view, "Selected: 5 (low confidence)") {
+		t.Errorf("player View() doesn't show the confidence:\n%s", view)
+	}
+
+	revealVotes()
+	if view := newMasterView().View(); !strings.Contains(view, "alice: 5 (low confidence)") {
+		t.Errorf("master View() doesn't show the confidence:\n%s", view)
+	}
+	if _, ok := cycleConfidence("alice"); ok {
+		t.Errorf("cycleConfidence() after reveal ok = true, want false")
+	}
+
+	nextRound()
+	if got := confidenceOf(); got != confidenceNone {
+		t.Errorf("confidence after next round = %q, want none", got)
+	}
+}
+
+// TestConfidenceSummary tests the aggregate of the confidence levels
+func TestConfidenceSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels []confidence
+		want   string
+	}{
+		{"no votes", nil, ""},
+		{"untagged votes", []confidence{confidenceNone, confidenceNone}, ""},
+		{"mixed", []confidence{confidenceLow, confidenceHigh, confidenceNone, confidenceHigh}, "🎯 Confidence: 2 high, 1 low\n"},
+		{"all levels", []confidence{confidenceMedium, confidenceLow, confidenceHigh}, "🎯 Confidence: 1 high, 1 med, 1 low\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := confidenceSummary(tt.levels); got != tt.want {
+				t.Errorf("confidenceSummary(%v) = %q, want %q", tt.levels, got, tt.want)
+			}
+		})
+	}
+}
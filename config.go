@@ -0,0 +1,535 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/log"
+)
+
+// Config is the configuration of the server, parsed from the command-line
+// flags, the SHOWDOWN_* environment variables, and the -config file, in that
+// order of precedence. The deck, the timer presets, the theme, and the options
+// become the game's with applySettings.
+//
+// Fields tagged with the name of their option in the -config file make up the
+// sample config written by showdown init.
+type Config struct {
+	Addr     string   // address the SSH server listens on
+	HostKeys []string // paths of the SSH host keys
+
+	Port           int    `json:"port"`            // port of the address
+	KeysPath       string `json:"keys"`            // authorized keys file of the Scrum Masters
+	MasterPassword string `json:"master-password"` // password granting the Scrum Master role, if any
+	RequireKey     bool   `json:"require-key"`     // refuse players without an SSH key
+
+	MaxConnections      int `json:"max-connections"`        // connections accepted at once
+	MaxConnectionsPerIP int `json:"max-connections-per-ip"` // connections accepted at once from one address
+
+	Deck             []string                 // cards of the deck
+	CardDescriptions map[string]string        // descriptions of the cards from the deck file
+	Timers           map[string]time.Duration // durations of the voting timer presets by key
+	Theme            Theme                    // colors of the interface
+
+	DeckPreset     string `json:"deck"`        // name of the deck preset
+	RoomConfigPath string `json:"room-config"` // file of the deck and timers of each room, if any
+
+	HTTPAddr   string `json:"http"`   // listen address of the web gateway, if any
+	APIAddr    string `json:"api"`    // listen address of the state API, if any
+	HealthAddr string `json:"health"` // listen address of the health endpoints, if any
+
+	StateFile  string `json:"state-file"` // file to snapshot the game state to, if any
+	EventsPath string `json:"events"`     // file to append the events to, if any
+	Room       string `json:"room"`       // name of the hosted room
+
+	MasterNamesPath  string `json:"master-names"` // file of the Scrum Master names, if any
+	FacilitatorsPath string `json:"facilitators"` // file of the facilitator keys, if any
+	BanlistPath      string `json:"banlist"`      // file of the ban list, if any
+
+	Check            bool // validate the configuration and exit
+	PrintFingerprint bool // print the host key fingerprints and exit
+
+	Options // settings of the game
+
+	// The backlog is imported from Jira or GitHub when set, writing revealed
+	// estimates back when enabled
+	jira          *jiraClient
+	jiraJQL       string
+	jiraWrite     bool
+	github        *githubClient
+	githubLabel   string
+	githubComment bool
+}
+
+// Options are the settings of the game, each set with the flag of the same
+// name.
+type Options struct {
+	Precision         int           `json:"precision"`          // decimals of the average and median
+	Chart             string        `json:"chart"`              // style of the distribution chart, chartBars or chartASCII
+	EstimateTime      bool          `json:"estimate-time"`      // estimate durations like 4h or 2d instead of points
+	Risk              bool          `json:"risk"`               // vote a second card for the risk after the effort
+	TimeoutAbstain    bool          `json:"timeout-abstain"`    // record ? for silent players when the voting timer expires
+	Spread            float64       `json:"spread"`             // flag rounds whose highest vote is this many times the lowest, 0 to disable
+	Compact           bool          `json:"compact"`            // show the distribution as one line per card without bars
+	PercentOfPlayers  bool          `json:"percent-of-players"` // compute the distribution percentages over all connected players
+	MinVoters         int           `json:"min-voters"`         // votes needed before the Scrum Master may reveal
+	NameLimit         int           `json:"name-limit"`         // most characters of a player name, and the width of the name input
+	MaxPlayers        int           `json:"max-players"`        // player capacity shown as a capacity bar, 0 for the maxPlayers cap without a bar
+	IdleTimeout       time.Duration `json:"idle-timeout"`       // disconnect players inactive for this long, 0 to disable
+	DuplicateSessions string        `json:"duplicate-sessions"` // duplicateTakeover or duplicateReject a second session of a player
+	MedianLower       bool          `json:"median-lower"`       // report the lower middle vote as median instead of the mean of both
+	TrimmedMean       bool          `json:"trimmed-mean"`       // add the average without the highest and lowest vote
+	Suggest           bool          `json:"suggest"`            // add the deck card nearest to the average
+	Leaderboard       bool          `json:"leaderboard"`        // add the fastest voter of the round to the results
+	RequireReady      bool          `json:"require-ready"`      // only start the voting timer once every player is ready
+	NoTimer           bool          `json:"no-timer"`           // disable the voting and discussion timers and their keys
+	NoBell            bool          `json:"no-bell"`            // don't ring the terminal bell when a timer expires
+	NoSuspense        bool          `json:"no-suspense"`        // reveal the votes without counting down first
+	BlindReveal       bool          `json:"blind-reveal"`       // reveal the votes to the Scrum Master only, until shared
+	AutoClear         int           `json:"auto-clear"`         // seconds after a reveal to start the next round, 0 to disable
+	Lang              string        `json:"lang"`               // language of the terminal views
+	Welcome           string        `json:"welcome"`            // banner above the name input, hidden when empty
+	NamePlaceholder   string        `json:"name-placeholder"`   // placeholder of the name input
+	Reserved          []string      `json:"reserved"`           // player names that are refused
+}
+
+// defaultConfig returns the defaults of the options of the configuration.
+func defaultConfig() Config {
+	return Config{
+		Port:                defaultPort,
+		MaxConnections:      defaultMaxConnections,
+		MaxConnectionsPerIP: defaultMaxConnectionsPerIP,
+		DeckPreset:          deckPresets[0].name,
+		Room:                "default",
+		Options:             defaultOptions,
+	}
+}
+
+// defaultOptions are the options of a game without flags.
+var defaultOptions = Options{
+	Precision:         1,
+	Chart:             chartBars,
+	Spread:            2.0,
+	NameLimit:         defaultNameLimit,
+	DuplicateSessions: duplicateTakeover,
+	Lang:              "en",
+	Welcome:           "Welcome to Showdown!",
+	NamePlaceholder:   "Enter your name",
+	Reserved:          []string{"master", "scrum master", "system", "admin"},
+}
+
+// options are the settings of the running game, set by applySettings.
+var options = defaultOptions
+
+// parseConfig parses the command-line arguments, without the program name,
+// into the configuration of the server. It loads the deck and room files, and
+// validates the combination of flags.
+func parseConfig(args []string) (Config, error) {
+	fs := flag.NewFlagSet("showdown", flag.ContinueOnError)
+	d := defaultConfig()
+	o := d.Options
+
+	// define flag for the file of option defaults
+	configPath := fs.String("config", "", "JSON file of option defaults keyed by flag name, overridden by SHOWDOWN_* variables and flags (disabled when empty)")
+	// define flag for custom port
+	port := fs.Int("p", d.Port, "SSH server port")
+	// define flag for the listen address
+	addr := fs.String("addr", "", "Host or IP address to listen on, e.g. 0.0.0.0 (default the hostname)")
+	// define flag for the optional web gateway for browser participants
+	httpAddr := fs.String("http", "", "Listen address of the web gateway for browser players, e.g. :8080 (disabled when empty)")
+	// define flag for the optional read-only JSON state API
+	apiAddr := fs.String("api", "", "Listen address of the read-only JSON state API, e.g. :8081 (disabled when empty)")
+	// define flag for the optional health endpoints
+	healthAddr := fs.String("health", "", "Listen address of the /healthz and /readyz endpoints, e.g. :8082 (disabled when empty)")
+	// define flag for the state file used for crash recovery
+	stateFile := fs.String("state-file", "", "File to snapshot the game state to and restore it from on startup (disabled when empty)")
+	// define flag for the events file for analytics
+	eventsPath := fs.String("events", "", "File to append join, vote, reveal, and clear events to as JSON lines (disabled when empty)")
+	// define repeatable flag for host keys, to allow rotating keys
+	var hostKeys hostKeyPaths
+	fs.Var(&hostKeys, "host-key", "Path to an SSH host key, repeatable (default .ssh/showdown_ed25519, generated when missing)")
+	// define flag for the authorized keys of the Scrum Masters
+	keysPath := fs.String("keys", "", "Authorized keys file of the Scrum Masters (default .ssh/showdown_keys)")
+	// define flags for the connection limits
+	maxConnections := fs.Int("max-connections", d.MaxConnections, "Maximum number of connections at once")
+	maxConnectionsPerIP := fs.Int("max-connections-per-ip", d.MaxConnectionsPerIP, "Maximum number of connections at once from one address")
+	// define flag for the master password fallback
+	masterPassword := fs.String("master-password", "", "Password granting the Scrum Master role to users without an authorized key (disabled when empty)")
+	// define flag for requiring public-key authentication
+	requireKey := fs.Bool("require-key", false, "Only accept players authenticated with an SSH key, disabling keyless joins")
+	// define flags for importing the backlog from Jira, authenticated with the
+	// JIRA_TOKEN and optional JIRA_USER environment variables
+	jiraURL := fs.String("jira", "", "Base URL of the Jira instance to import the backlog from (disabled when empty)")
+	jiraJQL := fs.String("jira-jql", "sprint in openSprints() ORDER BY rank", "JQL query selecting the Jira issues of the backlog")
+	jiraField := fs.String("jira-field", "", "Jira story point field to write revealed estimates to, e.g. customfield_10016 (disabled when empty)")
+	// define flags for importing the backlog from GitHub issues, authenticated
+	// with the GITHUB_TOKEN environment variable
+	githubRepo := fs.String("github", "", "GitHub repository owner/repo to import open issues from as the backlog (disabled when empty)")
+	githubLabel := fs.String("github-label", "", "Only import GitHub issues with this label")
+	githubComment := fs.Bool("github-comment", false, "Comment revealed estimates on the GitHub issues")
+	// define flag for validating the configuration without starting the server
+	check := fs.Bool("check", false, "Validate the configuration, print a report, and exit non-zero on any problem")
+	// define flag for printing the host key fingerprints
+	printFingerprint := fs.Bool("print-fingerprint", false, "Print the SHA256 fingerprint of each host key, generating missing keys, and exit")
+	// define flag for the names of the Scrum Masters by key fingerprint
+	masterNamesPath := fs.String("master-names", "", "File mapping SSH key fingerprints to Scrum Master names (disabled when empty)")
+	// define flag for the keys of the facilitators
+	facilitatorsPath := fs.String("facilitators", "", "File of SSH key fingerprints allowed to reveal, clear, and run timers next to the Scrum Master (disabled when empty)")
+	// define flag for the ban list of key fingerprints and IP ranges
+	banlistPath := fs.String("banlist", "", "File of SSH key fingerprints and CIDR ranges to refuse, reloaded on SIGHUP (disabled when empty)")
+	// define flag for the decimals of the statistics
+	fs.IntVar(&o.Precision, "precision", o.Precision, fmt.Sprintf("Number of decimals for average and median (0-%d)", maxPrecision))
+	// define flag for the distribution chart style
+	fs.StringVar(&o.Chart, "chart", o.Chart, fmt.Sprintf("Distribution chart style: %s or %s", chartBars, chartASCII))
+	// define flag for the deck preset
+	deckName := fs.String("deck", d.DeckPreset, fmt.Sprintf("Deck preset: %s", strings.Join(deckPresetNames(), ", ")))
+	// define flag for estimating time instead of points
+	fs.BoolVar(&o.EstimateTime, "estimate-time", o.EstimateTime, fmt.Sprintf("Estimate durations like 4h or 1d (a workday of %d hours) instead of points, with the deck %s unless -deck is set", workdayHours, strings.Join(timeDeck, ",")))
+	// define flag for a deck with card descriptions
+	deckFile := fs.String("deck-file", "", "JSON file of the deck's cards with their descriptions, instead of -deck")
+	// define flags for the per-room deck and timer settings
+	roomConfigPath := fs.String("room-config", "", "Path to a JSON file with the deck and timer presets of each room (disabled when empty)")
+	room := fs.String("room", d.Room, "Name of the room this server hosts, selecting its settings from the room config")
+	// define flag for overriding the distribution bar colors
+	gradient := fs.String("gradient", "", "Colors of the distribution bars as start,end hex colors, e.g. #eba0ac,#b4befe (default the theme's)")
+	// define flag for estimating the risk next to the effort
+	fs.BoolVar(&o.Risk, "risk", o.Risk, "Vote a second card for the risk of each story, after the effort")
+	// define flag for abstaining silent players on timer expiry
+	fs.BoolVar(&o.TimeoutAbstain, "timeout-abstain", o.TimeoutAbstain, "Record ? for players who haven't voted when the voting timer expires")
+	// define flag for the discussion alert on wide spreads
+	fs.Float64Var(&o.Spread, "spread", o.Spread, "Flag rounds whose highest vote is more than this many times the lowest for discussion (0 to disable)")
+	// define flag for the compact distribution
+	fs.BoolVar(&o.Compact, "compact", o.Compact, "Show the vote distribution as one line per card without bars")
+	// define flag for the votes needed before revealing
+	fs.IntVar(&o.MinVoters, "min-voters", o.MinVoters, "Only reveal the votes once at least this many players voted (disabled when 0)")
+	// define flag for the length of player names
+	fs.IntVar(&o.NameLimit, "name-limit", o.NameLimit, fmt.Sprintf("Maximum characters of player names, and the width of the name input (%d-%d)", minNameLength, maxNameLimit))
+	// define flag for the player capacity
+	fs.IntVar(&o.MaxPlayers, "max-players", o.MaxPlayers, fmt.Sprintf("Maximum number of players, shown to the Scrum Master as a capacity bar (default %d without the bar)", maxPlayers))
+	// define flag for disconnecting inactive players
+	fs.DurationVar(&o.IdleTimeout, "idle-timeout", o.IdleTimeout, "Disconnect players inactive for this long, warning them 30s before (disabled when 0)")
+	// define flag for the denominator of the distribution percentages
+	fs.BoolVar(&o.PercentOfPlayers, "percent-of-players", o.PercentOfPlayers, "Compute the distribution percentages over all connected players instead of those who voted")
+	// define flag for handling a second session of a connected player
+	fs.StringVar(&o.DuplicateSessions, "duplicate-sessions", o.DuplicateSessions, fmt.Sprintf("Second session of a connected player: %s the old one or %s the new one", duplicateTakeover, duplicateReject))
+	// define flag for reporting the lower-middle vote as median
+	fs.BoolVar(&o.MedianLower, "median-lower", o.MedianLower, "Report the lower of the two middle votes as median instead of their mean")
+	// define flag for disabling the timers
+	fs.BoolVar(&o.NoTimer, "no-timer", o.NoTimer, "Disable the voting and discussion timers and their keys")
+	// define flag for silencing the timer bell
+	fs.BoolVar(&o.NoBell, "no-bell", o.NoBell, "Don't ring the terminal bell when a timer expires")
+	// define flag for skipping the reveal countdown
+	fs.BoolVar(&o.NoSuspense, "no-suspense", o.NoSuspense, "Reveal the votes right away without the countdown")
+	// define flag for revealing the votes to the master first
+	fs.BoolVar(&o.BlindReveal, "blind-reveal", o.BlindReveal, "Reveal the votes to the Scrum Master only, until shared with R")
+	// define flag for clearing the round automatically after a reveal
+	fs.IntVar(&o.AutoClear, "auto-clear", o.AutoClear, "Start the next round this many seconds after the reveal (0 to disable)")
+	// define flag for showing the trimmed mean
+	fs.BoolVar(&o.TrimmedMean, "trimmed-mean", o.TrimmedMean, "Show the average without the highest and lowest vote in the statistics")
+	// define flag for showing the fastest voter
+	fs.BoolVar(&o.Leaderboard, "leaderboard", o.Leaderboard, "Show the fastest voter of each round in the results")
+	// define flag for requiring ready check-ins before the timer
+	fs.BoolVar(&o.RequireReady, "require-ready", o.RequireReady, "Only start the voting timer once every player is ready")
+	// define flag for suggesting the card nearest to the average
+	fs.BoolVar(&o.Suggest, "suggest", o.Suggest, "Show the deck card nearest to the average in the statistics")
+	// define flag for the language of the terminal views
+	fs.StringVar(&o.Lang, "lang", o.Lang, fmt.Sprintf("Language of the terminal views: %s", strings.Join(languages(), ", ")))
+	// define flags for branding the name input of joining players
+	fs.StringVar(&o.Welcome, "welcome", o.Welcome, "Banner shown above the name input of joining players (hidden when empty)")
+	fs.StringVar(&o.NamePlaceholder, "name-placeholder", o.NamePlaceholder, "Placeholder text of the name input of joining players")
+	// define flag for reserved player names
+	reserved := fs.String("reserved", strings.Join(o.Reserved, ","), "Comma-separated list of reserved player names")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if err := resolveOptions(fs, *configPath, os.Getenv); err != nil {
+		return Config{}, err
+	}
+
+	o.Reserved = parseReservedNames(*reserved)
+
+	if err := checkLanguage(o.Lang); err != nil {
+		return Config{}, err
+	}
+	// The join screen speaks the selected language unless it's branded
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	if !explicit["welcome"] {
+		o.Welcome = translate(o.Lang, "name.welcome")
+	}
+	if !explicit["name-placeholder"] {
+		o.NamePlaceholder = translate(o.Lang, "name.placeholder")
+	}
+
+	if o.Precision < 0 || o.Precision > maxPrecision {
+		return Config{}, fmt.Errorf("invalid precision %d (0-%d)", o.Precision, maxPrecision)
+	}
+	if o.MaxPlayers < 0 {
+		return Config{}, fmt.Errorf("invalid player limit %d", o.MaxPlayers)
+	}
+	if o.AutoClear < 0 {
+		return Config{}, fmt.Errorf("invalid auto-clear delay %d", o.AutoClear)
+	}
+	if o.NameLimit < minNameLength || o.NameLimit > maxNameLimit {
+		return Config{}, fmt.Errorf("invalid name limit %d (%d-%d)", o.NameLimit, minNameLength, maxNameLimit)
+	}
+	if o.Chart != chartBars && o.Chart != chartASCII {
+		return Config{}, fmt.Errorf("invalid chart style %q", o.Chart)
+	}
+	theme := defaultTheme
+	if *gradient != "" {
+		start, end, err := parseGradient(*gradient)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid gradient: %w", err)
+		}
+		theme.GradientStart, theme.GradientEnd = start, end
+	}
+	if o.DuplicateSessions != duplicateTakeover && o.DuplicateSessions != duplicateReject {
+		return Config{}, fmt.Errorf("invalid duplicate sessions mode %q", o.DuplicateSessions)
+	}
+	if *jiraURL != "" && *githubRepo != "" {
+		return Config{}, fmt.Errorf("import the backlog from either Jira or GitHub, not both")
+	}
+	if *requireKey && *masterPassword != "" {
+		return Config{}, fmt.Errorf("the master password needs keyless joins, which -require-key disables")
+	}
+	if *requireKey && *httpAddr != "" {
+		return Config{}, fmt.Errorf("the web gateway lets players join without a key, which -require-key disables")
+	}
+	if *maxConnections < 1 || *maxConnectionsPerIP < 1 {
+		return Config{}, fmt.Errorf("connection limits must be at least 1")
+	}
+
+	host, err := listenHost(*addr, os.Hostname)
+	if err != nil {
+		log.Warn("couldn't determine hostname, listening on all interfaces", "host", host, "error", err)
+	}
+
+	// Get absolute path for the default host key
+	if len(hostKeys) == 0 {
+		hostKeyPath, err := getConfigPath("showdown_ed25519")
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to resolve host key path: %w", err)
+		}
+		hostKeys = append(hostKeys, hostKeyPath)
+	}
+	for _, path := range hostKeys {
+		if err := validateHostKeyPath(path); err != nil {
+			return Config{}, fmt.Errorf("invalid host key: %w", err)
+		}
+	}
+	if *keysPath == "" {
+		if *keysPath, err = getConfigPath("showdown_keys"); err != nil {
+			return Config{}, fmt.Errorf("failed to resolve authorized keys path: %w", err)
+		}
+	}
+
+	// Use the deck preset or file, unless the hosted room has its own deck and
+	// timers
+	deck, ok := deckPreset(*deckName)
+	if !ok {
+		return Config{}, fmt.Errorf("unknown deck preset %q (presets: %s)", *deckName, strings.Join(deckPresetNames(), ", "))
+	}
+	if o.EstimateTime && !explicit["deck"] {
+		deck = timeDeck
+	}
+	descriptions := map[string]string{}
+	if *deckFile != "" {
+		if deck, descriptions, err = loadDeckFile(*deckFile); err != nil {
+			return Config{}, fmt.Errorf("failed to load deck file: %w", err)
+		}
+	}
+	timers := timerDurations
+	if *roomConfigPath != "" {
+		rooms, err := loadRoomConfig(*roomConfigPath)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to load room config: %w", err)
+		}
+		settings := rooms[*room]
+		if settings.Deck != nil {
+			deck = settings.Deck
+		}
+		if settings.Timers != nil {
+			if timers, err = parseTimerPresets(settings.Timers); err != nil {
+				return Config{}, fmt.Errorf("invalid settings of room %q: %w", *room, err)
+			}
+		}
+	}
+
+	cfg := Config{
+		Addr:                net.JoinHostPort(host, strconv.Itoa(*port)),
+		Port:                *port,
+		HostKeys:            hostKeys,
+		KeysPath:            *keysPath,
+		MasterPassword:      *masterPassword,
+		RequireKey:          *requireKey,
+		MaxConnections:      *maxConnections,
+		MaxConnectionsPerIP: *maxConnectionsPerIP,
+		DeckPreset:          *deckName,
+		Deck:                deck,
+		CardDescriptions:    descriptions,
+		Timers:              timers,
+		Theme:               theme,
+		RoomConfigPath:      *roomConfigPath,
+		HTTPAddr:            *httpAddr,
+		APIAddr:             *apiAddr,
+		HealthAddr:          *healthAddr,
+		StateFile:           *stateFile,
+		EventsPath:          *eventsPath,
+		Room:                *room,
+		MasterNamesPath:     *masterNamesPath,
+		FacilitatorsPath:    *facilitatorsPath,
+		BanlistPath:         *banlistPath,
+		Check:               *check,
+		PrintFingerprint:    *printFingerprint,
+		Options:             o,
+		jiraJQL:             *jiraJQL,
+		jiraWrite:           *jiraField != "",
+		githubLabel:         *githubLabel,
+		githubComment:       *githubComment,
+	}
+	if *jiraURL != "" {
+		cfg.jira = newJiraClient(*jiraURL, os.Getenv("JIRA_TOKEN"), os.Getenv("JIRA_USER"), *jiraField,
+			&http.Client{Timeout: 30 * time.Second})
+	}
+	if *githubRepo != "" {
+		if cfg.github, err = newGithubClient(*githubRepo, os.Getenv("GITHUB_TOKEN"), &http.Client{Timeout: 30 * time.Second}); err != nil {
+			return Config{}, fmt.Errorf("invalid GitHub repository: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// applySettings makes the deck, the timer presets, the theme, and the options
+// of the configuration those of the game, updating the help of the timer keys.
+func applySettings(cfg Config) {
+	pointOptions, cardDescriptions = cfg.Deck, cfg.CardDescriptions
+	timerDurations = cfg.Timers
+	theme = cfg.Theme
+	options = cfg.Options
+
+	bindings := map[string]*key.Binding{"f1": &keysMaster.One, "f3": &keysMaster.Three, "f6": &keysMaster.Six}
+	for k, binding := range bindings {
+		binding.SetHelp(binding.Help().Key, fmt.Sprintf("%d seconds", int(cfg.Timers[k].Seconds())))
+	}
+}
+
+// optionAliases maps the flags with short names to their names in the config
+// file and the environment.
+var optionAliases = map[string]string{"p": "port"}
+
+// optionName returns the name of a flag in the config file, like "port" for -p.
+func optionName(flagName string) string {
+	if alias, ok := optionAliases[flagName]; ok {
+		return alias
+	}
+	return flagName
+}
+
+// envName returns the environment variable of a flag, like SHOWDOWN_STATE_FILE
+// for -state-file.
+func envName(flagName string) string {
+	return "SHOWDOWN_" + strings.ToUpper(strings.ReplaceAll(optionName(flagName), "-", "_"))
+}
+
+// resolveOptions sets the flags not given on the command line from their
+// SHOWDOWN_* environment variables, and otherwise from the JSON config file at
+// path, which may itself be set with SHOWDOWN_CONFIG.
+func resolveOptions(fs *flag.FlagSet, path string, getenv func(string) string) error {
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+	if path == "" {
+		path = getenv(envName("config"))
+	}
+	var file map[string][]string
+	if path != "" {
+		var err error
+		if file, err = loadConfigFile(path); err != nil {
+			return err
+		}
+	}
+	for name := range file {
+		flagName := name
+		for short, alias := range optionAliases {
+			if alias == name {
+				flagName = short
+			}
+		}
+		if fs.Lookup(flagName) == nil || flagName == "config" {
+			return fmt.Errorf("config %s: unknown option %q", path, name)
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || given[f.Name] {
+			return
+		}
+		if value := getenv(envName(f.Name)); value != "" {
+			if setErr := fs.Set(f.Name, value); setErr != nil {
+				err = fmt.Errorf("%s: %w", envName(f.Name), setErr)
+			}
+			return
+		}
+		values := file[optionName(f.Name)]
+		// A list sets a repeatable option once per item, and any other option
+		// to its items joined like -reserved alice,bob
+		if _, repeatable := f.Value.(*hostKeyPaths); !repeatable && len(values) > 1 {
+			values = []string{strings.Join(values, ",")}
+		}
+		for _, value := range values {
+			if setErr := fs.Set(f.Name, value); setErr != nil {
+				err = fmt.Errorf("config %s: %s: %w", path, optionName(f.Name), setErr)
+				return
+			}
+		}
+	})
+	return err
+}
+
+// loadConfigFile reads the JSON config file at path: an object of option
+// names, as the flags without the dash and "port" for -p, to their values.
+// Strings, numbers, and booleans set an option, and lists of strings set a
+// repeatable option like host-key or a comma-separated one like reserved.
+func loadConfigFile(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	options := make(map[string][]string, len(raw))
+	for name, value := range raw {
+		switch v := value.(type) {
+		case string:
+			options[name] = []string{v}
+		case float64:
+			options[name] = []string{strconv.FormatFloat(v, 'f', -1, 64)}
+		case bool:
+			options[name] = []string{strconv.FormatBool(v)}
+		case []any:
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("config %s: %s must be a list of strings", path, name)
+				}
+				options[name] = append(options[name], s)
+			}
+		default:
+			return nil, fmt.Errorf("config %s: unsupported value of %s", path, name)
+		}
+	}
+	return options, nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestParseConfig tests parsing the flags into the server configuration and
+// refusing invalid combinations
+func TestParseConfig(t *testing.T) {
+	hostKey := filepath.Join(t.TempDir(), "showdown_ed25519")
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantAddr string
+		wantDeck []string
+		wantErr  bool
+	}{
+		{"address and port", []string{"-addr", "127.0.0.1", "-p", "2222"}, "127.0.0.1:2222", deckPresets[0].cards, false},
+		{"deck preset", []string{"-addr", "127.0.0.1", "-deck", "tshirt"}, "127.0.0.1:23234", deckPresets[1].cards, false},
+		{"unknown flag", []string{"-bogus"}, "", nil, true},
+		{"precision out of range", []string{"-addr", "127.0.0.1", "-precision", "9"}, "", nil, true},
+		{"unknown chart", []string{"-addr", "127.0.0.1", "-chart", "pie"}, "", nil, true},
+		{"unknown deck", []string{"-addr", "127.0.0.1", "-deck", "nope"}, "", nil, true},
+		{"both backlog imports", []string{"-addr", "127.0.0.1", "-jira", "https://jira", "-github", "o/r"}, "", nil, true},
+		{"master password with required key", []string{"-addr", "127.0.0.1", "-require-key", "-master-password", "pw"}, "", nil, true},
+		{"web gateway with required key", []string{"-addr", "127.0.0.1", "-require-key", "-http", ":8080"}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseConfig(append(tt.args, "-host-key", hostKey))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConfig(%q) err = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.Addr != tt.wantAddr {
+				t.Errorf("Addr = %q, want %q", cfg.Addr, tt.wantAddr)
+			}
+			if len(cfg.HostKeys) != 1 || cfg.HostKeys[0] != hostKey {
+				t.Errorf("HostKeys = %q, want [%s]", cfg.HostKeys, hostKey)
+			}
+			if !slices.Equal(cfg.Deck, tt.wantDeck) {
+				t.Errorf("Deck = %v, want %v", cfg.Deck, tt.wantDeck)
+			}
+		})
+	}
+
+	// The options of the game are only set on the configuration
+	cfg, err := parseConfig([]string{"-addr", "127.0.0.1", "-host-key", hostKey, "-max-players", "8", "-lang", "de"})
+	if err != nil {
+		t.Fatalf("parseConfig() err = %v", err)
+	}
+	if cfg.MaxPlayers != 8 || cfg.Lang != "de" || cfg.Welcome != translate("de", "name.welcome") {
+		t.Errorf("options = %+v, want 8 players in German", cfg.Options)
+	}
+	if options.MaxPlayers != 0 || options.Lang != "en" {
+		t.Errorf("parseConfig() changed the options of the game to %+v", options)
+	}
+}
+
+// TestApplySettings tests that the deck, timer presets, theme, and options of
+// the configuration become the game's
+func TestApplySettings(t *testing.T) {
+	defer func(deck []string, descriptions map[string]string, timers map[string]time.Duration, th Theme, keys keyMapMaster, o Options) {
+		pointOptions, cardDescriptions, timerDurations, theme, keysMaster, options = deck, descriptions, timers, th, keys, o
+	}(pointOptions, cardDescriptions, timerDurations, theme, keysMaster, options)
+
+	o := defaultOptions
+	o.MaxPlayers = 8
+	applySettings(Config{
+		Deck:             []string{"S", "M", "L"},
+		CardDescriptions: map[string]string{"L": "Split it"},
+		Timers:           map[string]time.Duration{"f1": 30 * time.Second, "f3": time.Minute, "f6": 2 * time.Minute},
+		Theme:            Theme{GradientStart: "#000000", GradientEnd: "#ffffff"},
+		Options:          o,
+	})
+	if options.MaxPlayers != 8 {
+		t.Errorf("options.MaxPlayers = %d, want 8", options.MaxPlayers)
+	}
+	if !slices.Equal(pointOptions, []string{"S", "M", "L"}) || cardDescriptions["L"] != "Split it" {
+		t.Errorf("pointOptions = %v, descriptions = %v, want S M L described", pointOptions, cardDescriptions)
+	}
+	if timerDurations["f6"] != 2*time.Minute {
+		t.Errorf("F6 timer = %v, want 2m", timerDurations["f6"])
+	}
+	if help := keysMaster.Six.Help().Desc; help != "120 seconds" {
+		t.Errorf("F6 help = %q, want 120 seconds", help)
+	}
+	if theme.GradientEnd != "#ffffff" {
+		t.Errorf("theme = %+v, want the configured one", theme)
+	}
+}
+
+// TestResolveOptions tests that flags take precedence over the environment,
+// which takes precedence over the config file
+func TestResolveOptions(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "showdown.json")
+	config := `{"port": 2200, "addr": "file", "api": "file", "require-key": true, "host-key": ["a", "b"], "reserved": ["alice", "bob"]}`
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	env := map[string]string{"SHOWDOWN_ADDR": "env", "SHOWDOWN_HTTP": "env", "SHOWDOWN_PORT": "2300"}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	port := fs.Int("p", 23234, "")
+	addr := fs.String("addr", "", "")
+	httpAddr := fs.String("http", "", "")
+	apiAddr := fs.String("api", "", "")
+	health := fs.String("health", "default", "")
+	requireKey := fs.Bool("require-key", false, "")
+	reserved := fs.String("reserved", "", "")
+	var hostKeys hostKeyPaths
+	fs.Var(&hostKeys, "host-key", "")
+	if err := fs.Parse([]string{"-addr", "flag"}); err != nil {
+		t.Fatalf("Parse() err = %v", err)
+	}
+
+	if err := resolveOptions(fs, configPath, func(key string) string { return env[key] }); err != nil {
+		t.Fatalf("resolveOptions() err = %v", err)
+	}
+	if *addr != "flag" {
+		t.Errorf("addr = %q, want the flag", *addr)
+	}
+	if *httpAddr != "env" || *port != 2300 {
+		t.Errorf("http = %q, port = %d, want the environment", *httpAddr, *port)
+	}
+	if *apiAddr != "file" || !*requireKey || !slices.Equal(hostKeys, hostKeyPaths{"a", "b"}) {
+		t.Errorf("api = %q, require-key = %v, host keys = %q, want the config file", *apiAddr, *requireKey, hostKeys)
+	}
+	if *reserved != "alice,bob" {
+		t.Errorf("reserved = %q, want the list of the config file joined", *reserved)
+	}
+	if *health != "default" {
+		t.Errorf("health = %q, want the default", *health)
+	}
+}
+
+// TestResolveOptionsErrors tests that invalid config files and values are
+// refused
+func TestResolveOptionsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		env    map[string]string
+	}{
+		{"unknown option", `{"colour": "red"}`, nil},
+		{"invalid file value", `{"port": "many"}`, nil},
+		{"nested value", `{"addr": {"host": "x"}}`, nil},
+		{"invalid environment value", `{}`, map[string]string{"SHOWDOWN_PORT": "many"}},
+		{"malformed file", `{"port":`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "showdown.json")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0o644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.Int("p", 23234, "")
+			fs.String("addr", "", "")
+
+			if err := resolveOptions(fs, configPath, func(key string) string { return tt.env[key] }); err == nil {
+				t.Errorf("resolveOptions() err = nil, want an error")
+			}
+		})
+	}
+}
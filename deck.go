@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// namedDeck is a deck preset the Scrum Master can switch to during a session.
+type namedDeck struct {
+	name  string
+	cards []string
+}
+
+// deckPresets are the named decks selectable with -deck and cycled through by
+// the Scrum Master, in order, starting with the default modified Fibonacci deck.
+var deckPresets = []namedDeck{
+	{"fibonacci", pointOptions},
+	{"tshirt", []string{"XS", "S", "M", "L", "XL", "?"}},
+	{"powers", []string{"1", "2", "4", "8", "16", "32", "?"}},
+	{"linear", []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "?"}},
+}
+
+// deckCard is a card of a deck file: its value and a description shown below
+// it in the player list, like "Needs a spike first" for "?".
+type deckCard struct {
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+// cardDescriptions maps card values to their descriptions from the -deck-file.
+var cardDescriptions = map[string]string{}
+
+// parseDeckFile reads a deck as a JSON array of cards, like
+//
+//	[{"value": "1", "description": "A few hours"}, {"value": "?", "description": "Needs a spike first"}]
+//
+// It returns the card values in order and the descriptions of the cards that
+// have one. The deck must not be empty and its values must be unique.
+func parseDeckFile(r io.Reader) ([]string, map[string]string, error) {
+	var cards []deckCard
+	if err := json.NewDecoder(r).Decode(&cards); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse deck file: %w", err)
+	}
+
+	deck := make([]string, len(cards))
+	descriptions := make(map[string]string)
+	for i, card := range cards {
+		deck[i] = card.Value
+		if card.Description != "" {
+			descriptions[card.Value] = card.Description
+		}
+	}
+	if err := checkDeck(deck); err != nil {
+		return nil, nil, err
+	}
+	return deck, descriptions, nil
+}
+
+// loadDeckFile reads the deck and card descriptions from the file at path.
+func loadDeckFile(path string) ([]string, map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open deck file: %w", err)
+	}
+	defer f.Close()
+
+	return parseDeckFile(f)
+}
+
+// deckPreset returns the cards of the named deck preset, or false when there's
+// no preset with that name.
+func deckPreset(name string) ([]string, bool) {
+	for _, preset := range deckPresets {
+		if preset.name == name {
+			return preset.cards, true
+		}
+	}
+	return nil, false
+}
+
+// deckPresetNames returns the names of the deck presets, in order.
+func deckPresetNames() []string {
+	names := make([]string, len(deckPresets))
+	for i, preset := range deckPresets {
+		names[i] = preset.name
+	}
+	return names
+}
+
+// activeDeck returns the deck of the session: the one the Scrum Master
+// switched to, or the configured pointOptions. Callers must hold state.mu.
+func activeDeck() []string {
+	if state.deck != nil {
+		return state.deck
+	}
+	return pointOptions
+}
+
+// currentDeck returns the deck of the session, for callers not holding
+// state.mu.
+func currentDeck() []string {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return activeDeck()
+}
+
+// setDeck switches the session to the given deck. Votes cast with the old deck
+// are cleared, and players rebuild their card list on their next tick, which
+// they notice by the changed deckVersion.
+func setDeck(deck []string) {
+	clearPlayerState()
+
+	state.mu.Lock()
+	state.deck = deck
+	state.deckVersion++
+	state.mu.Unlock()
+
+	logActivity("Deck switched to %s", strings.Join(deck, " "))
+}
+
+// nextDeckPreset returns the name of the preset after the one matching the
+// current deck, or of the first preset when the deck isn't a preset.
+func nextDeckPreset() string {
+	deck := currentDeck()
+	for i, preset := range deckPresets {
+		if slices.Equal(preset.cards, deck) {
+			return deckPresets[(i+1)%len(deckPresets)].name
+		}
+	}
+	return deckPresets[0].name
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestSetDeck tests that switching decks clears the votes and rebuilds the
+// card list of connected players
+func TestSetDeck(t *testing.T) {
+	state = newGameState()
+	model, _ := initPlayerView("alice", nil)
+	addPlayer("bob", nil)
+	model, _ = model.Update(keyRunes("5"))
+	castVote("bob", "8")
+
+	tshirt := []string{"XS", "S", "M", "L", "XL", "?"}
+	setDeck(tshirt)
+
+	state.mu.RLock()
+	deck := activeDeck()
+	for _, name := range []string{"alice", "bob"} {
+		if vote := state.players[name].vote(); vote.selected {
+			t.Errorf("%s still voted %q after switching decks", name, vote.points)
+		}
+	}
+	state.mu.RUnlock()
+	if !slices.Equal(deck, tshirt) {
+		t.Errorf("activeDeck() = %v, want %v", deck, tshirt)
+	}
+
+	model, _ = model.Update(tickMsg{})
+	p := model.(playerView)
+	if p.selected != "" {
+		t.Errorf("selected = %q after switching decks, want empty", p.selected)
+	}
+	var cards []string
+	for _, item := range p.list.Items() {
+		cards = append(cards, item.(PointItem).value)
+	}
+	if !slices.Equal(cards, tshirt) {
+		t.Errorf("card list = %v, want %v", cards, tshirt)
+	}
+
+	// Quick votes use the keys of the new deck
+	model.Update(keyRunes("M"))
+	state.mu.RLock()
+	vote := state.players["alice"].vote()
+	state.mu.RUnlock()
+	if vote.points != "M" {
+		t.Errorf("quick vote with M = %q, want M", vote.points)
+	}
+}
+
+// TestNextDeckPreset tests that the master cycles through the deck presets
+func TestNextDeckPreset(t *testing.T) {
+	state = newGameState()
+
+	var model tea.Model = newMasterView()
+	var names []string
+	for range len(deckPresets) + 1 {
+		names = append(names, nextDeckPreset())
+		model, _ = model.Update(keyRunes("p"))
+	}
+
+	want := []string{"tshirt", "powers", "linear", "fibonacci", "tshirt"}
+	if !slices.Equal(names, want) {
+		t.Errorf("presets = %v, want %v", names, want)
+	}
+	if deck := currentDeck(); !slices.Equal(deck, deckPresets[1].cards) {
+		t.Errorf("currentDeck() = %v, want %v", deck, deckPresets[1].cards)
+	}
+}
+
+// TestDeckPreset tests the cards of each deck preset
+func TestDeckPreset(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   []string
+		wantOK bool
+	}{
+		{"fibonacci", []string{"0.5", "1", "2", "3", "5", "8", "10", "?"}, true},
+		{"tshirt", []string{"XS", "S", "M", "L", "XL", "?"}, true},
+		{"powers", []string{"1", "2", "4", "8", "16", "32", "?"}, true},
+		{"linear", []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "?"}, true},
+		{"unknown", nil, false},
+		{"", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := deckPreset(tt.name)
+			if ok != tt.wantOK {
+				t.Fatalf("deckPreset(%q) ok = %v, want %v", tt.name, ok, tt.wantOK)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("deckPreset(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+			if ok {
+				if err := checkDeck(got); err != nil {
+					t.Errorf("deck preset %q is invalid: %v", tt.name, err)
+				}
+			}
+		})
+	}
+}
+
+// TestLoadDeckFile tests loading a deck with card descriptions and rejecting
+// empty decks and duplicate cards
+func TestLoadDeckFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deck.json")
+	content := `[
+		{"value": "1", "description": "A few hours"},
+		{"value": "3", "description": "A couple of days"},
+		{"value": "5"},
+		{"value": "?", "description": "Needs a spike first"}
+	]`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	deck, descriptions, err := loadDeckFile(path)
+	if err != nil {
+		t.Fatalf("loadDeckFile() err = %v", err)
+	}
+	if want := []string{"1", "3", "5", "?"}; !slices.Equal(deck, want) {
+		t.Errorf("deck = %v, want %v", deck, want)
+	}
+	if descriptions["?"] != "Needs a spike first" || len(descriptions) != 3 {
+		t.Errorf("descriptions = %v, want 3 with ? described", descriptions)
+	}
+
+	defer func(d map[string]string) { cardDescriptions = d }(cardDescriptions)
+	cardDescriptions = descriptions
+	if item := pointItems(deck)[0].(PointItem); item.Description() != "A few hours" {
+		t.Errorf("Description() of card 1 = %q, want %q", item.Description(), "A few hours")
+	}
+
+	for name, content := range map[string]string{
+		"empty":     `[]`,
+		"duplicate": `[{"value": "1"}, {"value": "1"}]`,
+		"not json":  `1,2,3`,
+	} {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := loadDeckFile(path); err == nil {
+			t.Errorf("loadDeckFile() of %s deck err = nil, want error", name)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// eventFlushInterval is how often buffered events are written to the events
+// file.
+const eventFlushInterval = time.Second
+
+// event is one line of the events file: a player joining or voting, or the
+// votes of a round being revealed or cleared.
+type event struct {
+	Time     time.Time         `json:"time"`
+	Room     string            `json:"room"`
+	Type     string            `json:"type"`
+	Round    int               `json:"round"`
+	Player   string            `json:"player,omitempty"`
+	Points   string            `json:"points,omitempty"`
+	Votes    map[string]string `json:"votes,omitempty"`
+	Estimate string            `json:"estimate,omitempty"`
+}
+
+// eventLog appends events as JSON lines to a file for analytics pipelines.
+// Lines are buffered and written whole, so concurrent sessions don't
+// interleave them. Once closed, events are dropped.
+type eventLog struct {
+	mu     sync.Mutex
+	file   *os.File
+	buf    *bufio.Writer
+	room   string
+	closed bool
+}
+
+// events is the events file set with -events, or nil when disabled.
+var events *eventLog
+
+// openEventLog opens the events file at path for appending, creating it when
+// missing. Events are recorded for the given room.
+func openEventLog(path, room string) (*eventLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &eventLog{file: file, buf: bufio.NewWriter(file), room: room}, nil
+}
+
+// emit buffers an event, stamped with the current time and the room. It does
+// nothing when the events file is disabled or closed.
+func (l *eventLog) emit(e event) {
+	if l == nil {
+		return
+	}
+	e.Time = time.Now()
+	e.Room = l.room
+	line, err := json.Marshal(e)
+	if err != nil {
+		log.Error("failed to serialize event", "error", err, "type", e.Type)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	l.buf.Write(append(line, '\n'))
+}
+
+// flush writes the buffered events to the file, unless it's closed.
+func (l *eventLog) flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	return l.buf.Flush()
+}
+
+// Close writes the buffered events and closes the file. Closing it again does
+// nothing.
+func (l *eventLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	if err := l.buf.Flush(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
+
+// flushEvents periodically writes the buffered events to the file, until done
+// is closed.
+func (l *eventLog) flushEvents(done <-chan struct{}) {
+	ticker := time.NewTicker(eventFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := l.flush(); err != nil {
+				log.Error("failed to write events", "error", err, "path", l.file.Name())
+			}
+		}
+	}
+}
+
+// revealEvent returns the reveal event of the current round with the vote of
+// each player and the estimate. Callers must hold state.mu.
+func revealEvent() event {
+	votes := make(map[string]string)
+	for _, player := range state.players {
+		if vote := player.vote(); vote.selected {
+			votes[player.name] = vote.points
+		}
+	}
+	return event{Type: "reveal", Round: state.round, Votes: votes, Estimate: roundEstimate()}
+}
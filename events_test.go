@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRevealEvent tests that revealing the votes appends a well-formed JSON
+// line with the votes of the round
+func TestRevealEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	l, err := openEventLog(path, "team-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events = l
+	defer func() { events = nil }()
+
+	state = newGameState()
+	addPlayer("alice", nil)
+	addPlayer("bob", nil)
+	castVote("alice", "3")
+	castVote("bob", "5")
+	revealVotes()
+	if err := events.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 2 joins, 2 votes, and the reveal:\n%s", len(lines), data)
+	}
+
+	var got event
+	if err := json.Unmarshal([]byte(lines[4]), &got); err != nil {
+		t.Fatalf("reveal line %q isn't valid JSON: %v", lines[4], err)
+	}
+	if got.Type != "reveal" || got.Room != "team-a" || got.Time.IsZero() {
+		t.Errorf("reveal event = %+v, want type reveal in room team-a with a time", got)
+	}
+	if got.Votes["alice"] != "3" || got.Votes["bob"] != "5" || len(got.Votes) != 2 {
+		t.Errorf("votes = %v, want alice 3 and bob 5", got.Votes)
+	}
+}
+
+// TestEventLogClosed tests that events emitted after closing the events file
+// are dropped without writing to the closed file
+func TestEventLogClosed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	l, err := openEventLog(path, "team-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.emit(event{Type: "join", Player: "alice"})
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l.emit(event{Type: "leave", Player: "alice"})
+	if err := l.flush(); err != nil {
+		t.Errorf("flush() after Close() err = %v, want nil", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("second Close() err = %v, want nil", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("got %d lines, want the join only:\n%s", lines, data)
+	}
+}
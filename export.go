@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// PlayerVote is a single player's vote in a round result.
+type PlayerVote struct {
+	Name   string
+	Points string
+	Voted  bool
+}
+
+// RoundResult is a revealed round, as exported for wikis and ticketing tools.
+type RoundResult struct {
+	Round   int
+	Attempt int
+	Votes   []PlayerVote
+}
+
+// currentRoundResult builds the result of the current round from the game
+// state, with players sorted by name.
+func currentRoundResult() RoundResult {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	result := RoundResult{
+		Round:   state.round,
+		Attempt: state.attempt,
+		Votes:   make([]PlayerVote, 0, len(state.players)),
+	}
+
+	keys := make([]string, 0, len(state.players))
+	for key := range state.players {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		player := state.players[key]
+		vote := player.vote()
+		result.Votes = append(result.Votes, PlayerVote{
+			Name:   player.name,
+			Points: vote.points,
+			Voted:  vote.selected,
+		})
+	}
+
+	return result
+}
+
+// formatMarkdown formats a round result as a Markdown table of player votes
+// followed by a summary line with the vote count, average, and median.
+func formatMarkdown(result RoundResult) string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "### Round %d.%d\n\n", result.Round, result.Attempt)
+	s.WriteString("| Player | Points |\n")
+	s.WriteString("| --- | --- |\n")
+
+	var points []string
+	for _, vote := range result.Votes {
+		if vote.Voted {
+			fmt.Fprintf(&s, "| %s | %s |\n", vote.Name, vote.Points)
+			points = append(points, vote.Points)
+		} else {
+			fmt.Fprintf(&s, "| %s | no vote |\n", vote.Name)
+		}
+	}
+
+	avg, median, _ := calculateStatistics(points)
+	fmt.Fprintf(&s, "\n**Votes:** %d/%d", len(points), len(result.Votes))
+	if avg > 0 {
+		fmt.Fprintf(&s, " · **Average:** %s", formatStat(avg))
+	}
+	fmt.Fprintf(&s, " · **Median:** %s\n", median)
+
+	return s.String()
+}
+
+// formatPlainText formats a round result as plain text to copy from the
+// terminal: one line per player with their vote, aligned, followed by a summary
+// line with the vote count, average, and median.
+func formatPlainText(result RoundResult) string {
+	width := 0
+	for _, vote := range result.Votes {
+		width = max(width, len(vote.Name))
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "Round %d.%d\n\n", result.Round, result.Attempt)
+
+	var points []string
+	for _, vote := range result.Votes {
+		if vote.Voted {
+			fmt.Fprintf(&s, "%-*s  %s\n", width, vote.Name, vote.Points)
+			points = append(points, vote.Points)
+		} else {
+			fmt.Fprintf(&s, "%-*s  no vote\n", width, vote.Name)
+		}
+	}
+
+	avg, median, _ := calculateStatistics(points)
+	fmt.Fprintf(&s, "\nVotes: %d/%d", len(points), len(result.Votes))
+	if avg > 0 {
+		fmt.Fprintf(&s, "  Average: %s", formatStat(avg))
+	}
+	fmt.Fprintf(&s, "  Median: %s\n", median)
+
+	return s.String()
+}
+
+// exportMarkdown writes the current round as Markdown to a file in the working
+// directory and returns its name.
+func exportMarkdown() (string, error) {
+	result := currentRoundResult()
+	filename := fmt.Sprintf("showdown-round-%d.%d.md", result.Round, result.Attempt)
+	if err := os.WriteFile(filename, []byte(formatMarkdown(result)), 0o644); err != nil {
+		return "", fmt.Errorf("failed to export results: %w", err)
+	}
+	return filename, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFormatMarkdown tests the Markdown table export of a round
+func TestFormatMarkdown(t *testing.T) {
+	result := RoundResult{
+		Round:   2,
+		Attempt: 1,
+		Votes: []PlayerVote{
+			{Name: "alice", Points: "3", Voted: true},
+			{Name: "bob", Points: "5", Voted: true},
+			{Name: "carol"},
+		},
+	}
+
+	want := "### Round 2.1\n\n" +
+		"| Player | Points |\n" +
+		"| --- | --- |\n" +
+		"| alice | 3 |\n" +
+		"| bob | 5 |\n" +
+		"| carol | no vote |\n" +
+		"\n**Votes:** 2/3 · **Average:** 4.0 · **Median:** 4.0\n"
+
+	if got := formatMarkdown(result); got != want {
+		t.Errorf("formatMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestFormatPlainText tests the plain text summary of a round to copy from
+// the terminal
+func TestFormatPlainText(t *testing.T) {
+	result := RoundResult{
+		Round:   2,
+		Attempt: 1,
+		Votes: []PlayerVote{
+			{Name: "alice", Points: "3", Voted: true},
+			{Name: "bob", Points: "5", Voted: true},
+			{Name: "carol"},
+		},
+	}
+
+	want := "Round 2.1\n\n" +
+		"alice  3\n" +
+		"bob    5\n" +
+		"carol  no vote\n" +
+		"\nVotes: 2/3  Average: 4.0  Median: 4.0\n"
+
+	if got := formatPlainText(result); got != want {
+		t.Errorf("formatPlainText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestFormatMarkdownNoNumericVotes tests the summary without numeric votes
+func TestFormatMarkdownNoNumericVotes(t *testing.T) {
+	result := RoundResult{
+		Round:   1,
+		Attempt: 1,
+		Votes:   []PlayerVote{{Name: "alice", Points: "?", Voted: true}},
+	}
+
+	got := formatMarkdown(result)
+	if strings.Contains(got, "Average") {
+		t.Errorf("formatMarkdown() contains average without numeric votes:\n%s", got)
+	}
+	if !strings.Contains(got, "**Median:** N/A") {
+		t.Errorf("formatMarkdown() missing N/A median:\n%s", got)
+	}
+}
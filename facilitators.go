@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/ssh"
+)
+
+// facilitatorKeys holds the SSH key fingerprints of the facilitators, who help
+// the Scrum Master run the session: they reveal and clear the votes and run
+// the timers, next to the single Scrum Master. It is loaded from the
+// -facilitators file at startup.
+var facilitatorKeys = map[string]bool{}
+
+// parseFacilitators reads the facilitator keys with one SHA256 key fingerprint
+// per line, as printed by ssh-keygen -l, optionally followed by a comment.
+// Empty lines and lines starting with # are ignored.
+func parseFacilitators(r io.Reader) (map[string]bool, error) {
+	keys := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fingerprint, _, _ := strings.Cut(line, " ")
+		if !strings.HasPrefix(fingerprint, "SHA256:") {
+			return nil, fmt.Errorf("line %d: expected a key fingerprint, got %q", lineNo, fingerprint)
+		}
+		keys[fingerprint] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// loadFacilitators replaces the facilitator keys with the entries of the file
+// at path.
+func loadFacilitators(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open facilitators: %w", err)
+	}
+	defer f.Close()
+
+	keys, err := parseFacilitators(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse facilitators %s: %w", path, err)
+	}
+	facilitatorKeys = keys
+
+	return nil
+}
+
+// isFacilitator reports whether the session authenticated with a facilitator
+// key.
+func isFacilitator(s ssh.Session) bool {
+	fingerprint := keyFingerprint(s.PublicKey())
+	return fingerprint != "" && facilitatorKeys[fingerprint]
+}
+
+// newFacilitatorView creates the view of a facilitator: the master view
+// limited to revealing, clearing, and the timers, which leaves the players,
+// the backlog, the session settings, and the live tally to the Scrum Master.
+func newFacilitatorView() masterView {
+	m := newMasterView()
+	m.facilitator = true
+	for _, binding := range []*key.Binding{
+		&m.keys.Skip, &m.keys.Freeze, &m.keys.Deck, &m.keys.Export, &m.keys.Copy,
+		&m.keys.Disconnect, &m.keys.RevealOne, &m.keys.Whisper, &m.keys.Announce,
+		&m.keys.Tally,
+	} {
+		binding.SetEnabled(false)
+	}
+	return m
+}
+
+// addFacilitator registers the session of a facilitator in the game state.
+func addFacilitator(s ssh.Session) {
+	state.mu.Lock()
+	state.facilitators[s] = true
+	state.mu.Unlock()
+	logActivity("Facilitator %s joined", s.User())
+}
+
+// removeFacilitator forgets the session of a facilitator who left. Callers
+// must hold state.mu.
+func removeFacilitator(s ssh.Session) {
+	if state.facilitators[s] {
+		delete(state.facilitators, s)
+		logActivity("Facilitator %s left", s.User())
+	}
+}
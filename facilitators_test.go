@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// TestIsFacilitator tests recognizing facilitators by the fingerprint of
+// their key
+func TestIsFacilitator(t *testing.T) {
+	defer func(keys map[string]bool) { facilitatorKeys = keys }(facilitatorKeys)
+
+	known := newTestPublicKey(t)
+	path := filepath.Join(t.TempDir(), "facilitators.txt")
+	content := "# Facilitators\n" + gossh.FingerprintSHA256(known) + " alice's laptop\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadFacilitators(path); err != nil {
+		t.Fatalf("loadFacilitators() err = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		key  gossh.PublicKey
+		want bool
+	}{
+		{"listed key", known, true},
+		{"other key", newTestPublicKey(t), false},
+		{"no key", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFacilitator(&stubSession{key: tt.key}); got != tt.want {
+				t.Errorf("isFacilitator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseFacilitators(strings.NewReader("alice\n")); err == nil {
+		t.Errorf("parseFacilitators() of a line without fingerprint err = nil, want error")
+	}
+}
+
+// TestFacilitatorRights tests that a facilitator can reveal the votes but a
+// plain player cannot, and that the Scrum Master's other keys are disabled
+func TestFacilitatorRights(t *testing.T) {
+	state = newGameState()
+	options.NoSuspense = true
+	defer func() { options.NoSuspense = false }()
+
+	player, _ := initPlayerView("alice", nil)
+	player, _ = player.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+
+	// "r" is the player's ready key, it doesn't reveal
+	player.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	state.mu.RLock()
+	revealed := state.masterRevealed
+	state.mu.RUnlock()
+	if revealed {
+		t.Fatalf("player revealed the votes")
+	}
+
+	var model tea.Model = newFacilitatorView()
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	state.mu.RLock()
+	revealed = state.masterRevealed
+	state.mu.RUnlock()
+	if !revealed {
+		t.Errorf("facilitator didn't reveal the votes")
+	}
+	if view := model.View(); !strings.Contains(view, "Facilitator") {
+		t.Errorf("facilitator View() doesn't show the role:\n%s", view)
+	}
+
+	keys := model.(masterView).keys
+	if !keys.Clear.Enabled() || !keys.One.Enabled() {
+		t.Errorf("facilitator can't clear or start timers")
+	}
+	if keys.Disconnect.Enabled() || keys.Deck.Enabled() || keys.Tally.Enabled() {
+		t.Errorf("facilitator can disconnect players, switch decks, or see the live tally")
+	}
+
+	// Quitting leaves the Scrum Master alone
+	master := &stubSession{}
+	state.mu.Lock()
+	state.masters[master] = "Robin"
+	state.mu.Unlock()
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if _, ok := state.masters[master]; !ok {
+		t.Errorf("facilitator quitting reset the Scrum Master")
+	}
+}
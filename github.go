@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// githubAPI is the base URL of the GitHub REST API.
+	githubAPI = "https://api.github.com"
+
+	// githubPageSize is how many issues are requested per GitHub page.
+	githubPageSize = 100
+)
+
+// githubClient imports the backlog from the open issues of a GitHub repository
+// and writes estimates back as issue comments.
+type githubClient struct {
+	baseURL string
+	repo    string
+	token   string
+	http    httpClient
+}
+
+// githubIssue is the part of a GitHub issue the backlog uses. Pull requests
+// are listed as issues too and have PullRequest set.
+type githubIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+// newGithubClient returns a GitHub client for the owner/repo repository.
+func newGithubClient(repo, token string, client httpClient) (*githubClient, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid GitHub repository %q, expected owner/repo", repo)
+	}
+	return &githubClient{baseURL: githubAPI, repo: repo, token: token, http: client}, nil
+}
+
+// parseGithubIssues decodes a page of GitHub issues into stories titled like
+// "#123: Fix login", skipping pull requests. It also returns the number of
+// entries of the page, including pull requests, to detect the last page.
+func parseGithubIssues(r io.Reader) ([]Story, int, error) {
+	var issues []githubIssue
+	if err := json.NewDecoder(r).Decode(&issues); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse GitHub issues: %w", err)
+	}
+
+	stories := make([]Story, 0, len(issues))
+	for _, issue := range issues {
+		if len(issue.PullRequest) > 0 {
+			continue
+		}
+		stories = append(stories, Story{ID: "#" + strconv.Itoa(issue.Number), Title: issue.Title})
+	}
+	return stories, len(issues), nil
+}
+
+// do sends an authenticated request to the GitHub REST API and returns the
+// response body, failing on any status other than want.
+func (c *githubClient) do(method, path string, body io.Reader, want int) ([]byte, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != want {
+		return nil, fmt.Errorf("github %s %s: %s", method, path, resp.Status)
+	}
+	return data, nil
+}
+
+// openIssues returns the open issues with the label as stories, fetching all
+// pages. An empty label returns all open issues.
+func (c *githubClient) openIssues(label string) ([]Story, error) {
+	var stories []Story
+	for page := 1; ; page++ {
+		query := url.Values{
+			"state":    {"open"},
+			"per_page": {strconv.Itoa(githubPageSize)},
+			"page":     {strconv.Itoa(page)},
+		}
+		if label != "" {
+			query.Set("labels", label)
+		}
+		data, err := c.do(http.MethodGet, "/repos/"+c.repo+"/issues?"+query.Encode(), nil, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+
+		issues, n, err := parseGithubIssues(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, issues...)
+		if n < githubPageSize {
+			return stories, nil
+		}
+	}
+}
+
+// writeEstimate comments the estimate on the story's issue.
+func (c *githubClient) writeEstimate(story Story, estimate string) error {
+	number := strings.TrimPrefix(story.ID, "#")
+	if _, err := strconv.Atoi(number); err != nil {
+		return fmt.Errorf("story %q is not a GitHub issue", story.ID)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"body": fmt.Sprintf("Estimated at %s story points in Showdown.", estimate),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.do(http.MethodPost, "/repos/"+c.repo+"/issues/"+number+"/comments", bytes.NewReader(body), http.StatusCreated)
+	return err
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestParseGithubIssues tests decoding a sample issues payload into stories
+func TestParseGithubIssues(t *testing.T) {
+	payload := `[
+		{"number": 123, "title": "Fix login", "state": "open", "labels": [{"name": "estimate"}]},
+		{"number": 124, "title": "Bump dependencies", "pull_request": {"url": "https://api.github.com/repos/o/r/pulls/124"}},
+		{"number": 125, "title": "Add dark mode", "state": "open"}
+	]`
+
+	stories, n, err := parseGithubIssues(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("parseGithubIssues() err = %v", err)
+	}
+	want := []Story{{ID: "#123", Title: "Fix login"}, {ID: "#125", Title: "Add dark mode"}}
+	if !slices.Equal(stories, want) || n != 3 {
+		t.Errorf("parseGithubIssues() = %v, %d, want %v, 3", stories, n, want)
+	}
+
+	state = newGameState()
+	setBacklog(stories)
+	if got := storyView(); got != "📝 #123: Fix login\n\n" {
+		t.Errorf("storyView() = %q, want #123: Fix login", got)
+	}
+}
+
+// TestNewGithubClientRepo tests validating the owner/repo argument
+func TestNewGithubClientRepo(t *testing.T) {
+	for _, repo := range []string{"", "showdown", "/showdown", "cmdrrobin/", "a/b/c"} {
+		if _, err := newGithubClient(repo, "", nil); err == nil {
+			t.Errorf("newGithubClient(%q) err = nil, want error", repo)
+		}
+	}
+	if _, err := newGithubClient("cmdrrobin/showdown", "", nil); err != nil {
+		t.Errorf("newGithubClient() err = %v", err)
+	}
+}
+
+// TestGithubOpenIssuesAndComment tests listing labeled issues and commenting
+// the estimate with a stubbed client
+func TestGithubOpenIssuesAndComment(t *testing.T) {
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `[{"number": 7, "title": "Fix login"}]`),
+		stubResponse(http.StatusCreated, `{"id": 1}`),
+	}}
+	client, err := newGithubClient("cmdrrobin/showdown", "secret", stub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stories, err := client.openIssues("needs estimate")
+	if err != nil {
+		t.Fatalf("openIssues() err = %v", err)
+	}
+	if len(stories) != 1 || stories[0].ID != "#7" {
+		t.Errorf("openIssues() = %v, want #7", stories)
+	}
+	req := stub.requests[0]
+	if req.URL.Path != "/repos/cmdrrobin/showdown/issues" || req.URL.Query().Get("labels") != "needs estimate" {
+		t.Errorf("issues request = %s", req.URL)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q, want bearer token", got)
+	}
+
+	if err := client.writeEstimate(stories[0], "5"); err != nil {
+		t.Fatalf("writeEstimate() err = %v", err)
+	}
+	req = stub.requests[1]
+	if req.Method != http.MethodPost || req.URL.Path != "/repos/cmdrrobin/showdown/issues/7/comments" {
+		t.Errorf("comment request = %s %s", req.Method, req.URL.Path)
+	}
+	if !strings.Contains(stub.bodies[1], "5 story points") {
+		t.Errorf("comment body = %s, want the estimate", stub.bodies[1])
+	}
+
+	if err := client.writeEstimate(Story{ID: "PROJ-1"}, "5"); err == nil {
+		t.Errorf("writeEstimate() of a Jira story err = nil, want error")
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// sshReady reports whether the SSH listener is accepting connections. It's set
+// once the listener is bound and cleared again when the server shuts down.
+var sshReady atomic.Bool
+
+// serveHealthz handles GET /healthz, responding 200 as long as the process is
+// alive.
+func serveHealthz(w http.ResponseWriter, r *http.Request) {
+	io.WriteString(w, "ok\n")
+}
+
+// serveReadyz handles GET /readyz, responding 200 while the SSH listener is
+// accepting connections and 503 Service Unavailable before it's bound and
+// during shutdown.
+func serveReadyz(w http.ResponseWriter, r *http.Request) {
+	if !sshReady.Load() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	io.WriteString(w, "ready\n")
+}
+
+// newHealthHandler returns the HTTP handler of the health endpoints, serving
+// liveness on /healthz and readiness on /readyz for orchestrators like
+// Kubernetes.
+func newHealthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", serveHealthz)
+	mux.HandleFunc("/readyz", serveReadyz)
+	return mux
+}
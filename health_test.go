@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestHealthEndpoints tests liveness and readiness before, while, and after the
+// SSH listener accepts connections
+func TestHealthEndpoints(t *testing.T) {
+	defer sshReady.Store(false)
+
+	tests := []struct {
+		name       string
+		ready      bool
+		path       string
+		wantStatus int
+	}{
+		{"alive before listening", false, "/healthz", http.StatusOK},
+		{"not ready before listening", false, "/readyz", http.StatusServiceUnavailable},
+		{"alive while listening", true, "/healthz", http.StatusOK},
+		{"ready while listening", true, "/readyz", http.StatusOK},
+	}
+
+	handler := newHealthHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sshReady.Store(tt.ready)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("GET %s status = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
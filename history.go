@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// roundRecord is the history entry of a revealed round attempt, recording when
+// voting started and when the votes were revealed. A skipped story is recorded
+// with its ID and no voting time.
+type roundRecord struct {
+	round   int
+	attempt int
+	start   time.Time
+	end     time.Time
+	story   string
+	skipped bool
+}
+
+// duration returns how long voting took in the round.
+func (r roundRecord) duration() time.Duration {
+	return r.end.Sub(r.start)
+}
+
+// averageRoundDuration returns the mean voting time of the recorded rounds,
+// leaving out skipped stories, or zero when there are none.
+func averageRoundDuration(history []roundRecord) time.Duration {
+	var total time.Duration
+	rounds := 0
+	for _, r := range history {
+		if r.skipped {
+			continue
+		}
+		total += r.duration()
+		rounds++
+	}
+	if rounds == 0 {
+		return 0
+	}
+	return total / time.Duration(rounds)
+}
+
+// formatDuration formats a duration as minutes and seconds, like 0:42.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// recordRound appends the current round attempt to the history when votes are
+// revealed, unless voting never started or it was already recorded. It reports
+// whether the attempt was recorded. Callers must hold state.mu.
+func recordRound(now time.Time) bool {
+	if state.votingStart.IsZero() {
+		return false
+	}
+	if n := len(state.history); n > 0 {
+		last := state.history[n-1]
+		if last.round == state.round && last.attempt == state.attempt {
+			return false
+		}
+	}
+	state.history = append(state.history, roundRecord{
+		round:   state.round,
+		attempt: state.attempt,
+		start:   state.votingStart,
+		end:     now,
+	})
+	return true
+}
+
+// recordSkip appends the current round to the history as a skipped story,
+// without an estimate or voting time. Callers must hold state.mu.
+func recordSkip(story Story, now time.Time) {
+	state.history = append(state.history, roundRecord{
+		round:   state.round,
+		attempt: state.attempt,
+		start:   now,
+		end:     now,
+		story:   story.ID,
+		skipped: true,
+	})
+}
+
+// sessionSummary returns the recap printed when the server shuts down: the
+// number of rounds played, re-votes not counting as rounds of their own, and
+// of players who joined, and the average round duration from the history,
+// like "Session summary: rounds 4, players 6, average round 1:30".
+func sessionSummary(history []roundRecord, rounds, players int) string {
+	summary := fmt.Sprintf("Session summary: rounds %d, players %d", rounds, players)
+	if rounds > 0 {
+		summary += ", average round " + formatDuration(averageRoundDuration(history))
+	}
+	return summary
+}
+
+// roundTimingView renders how long the current round took and the typical
+// round duration, shown with the revealed results. Callers must hold state.mu.
+func roundTimingView() string {
+	n := len(state.history)
+	if n == 0 {
+		return ""
+	}
+	last := state.history[n-1]
+	if last.round != state.round || last.attempt != state.attempt {
+		return ""
+	}
+	return t("stats.roundTiming",
+		formatDuration(last.duration()),
+		formatDuration(averageRoundDuration(state.history))) + "\n"
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRoundDuration tests the duration computation of round records
+func TestRoundDuration(t *testing.T) {
+	start := time.Date(2024, 11, 15, 10, 0, 0, 0, time.UTC)
+	history := []roundRecord{
+		{round: 1, attempt: 1, start: start, end: start.Add(42 * time.Second)},
+		{round: 2, attempt: 1, start: start, end: start.Add(68 * time.Second)},
+	}
+
+	if got := history[0].duration(); got != 42*time.Second {
+		t.Errorf("duration() = %v, want 42s", got)
+	}
+	if got := averageRoundDuration(history); got != 55*time.Second {
+		t.Errorf("averageRoundDuration() = %v, want 55s", got)
+	}
+	if got := averageRoundDuration(nil); got != 0 {
+		t.Errorf("averageRoundDuration(nil) = %v, want 0", got)
+	}
+	skipped := append(history, roundRecord{round: 3, attempt: 1, start: start, end: start, story: "PROJ-3", skipped: true})
+	if got := averageRoundDuration(skipped); got != 55*time.Second {
+		t.Errorf("averageRoundDuration() with a skipped story = %v, want 55s", got)
+	}
+
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{42 * time.Second, "0:42"},
+		{65 * time.Second, "1:05"},
+		{1500 * time.Millisecond, "0:02"},
+		{10 * time.Minute, "10:00"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %s, want %s", tt.d, got, tt.want)
+		}
+	}
+}
+
+// TestRecordRound tests that a round is recorded once on reveal after voting started
+func TestRecordRound(t *testing.T) {
+	state = newGameState()
+	addPlayer("alice", nil)
+
+	// Without any vote there is nothing to time
+	revealVotes()
+	if len(state.history) != 0 {
+		t.Fatalf("history after reveal without votes = %d records, want 0", len(state.history))
+	}
+
+	revote()
+	castVote("alice", "5")
+	revealVotes()
+	revealVotes()
+
+	if len(state.history) != 1 {
+		t.Fatalf("history = %d records, want 1", len(state.history))
+	}
+	if record := state.history[0]; record.round != 1 || record.attempt != 2 || record.end.Before(record.start) {
+		t.Errorf("record = %+v, want round 1.2 with end after start", record)
+	}
+}
+
+// TestSessionSummary tests the recap printed on shutdown
+func TestSessionSummary(t *testing.T) {
+	start := time.Date(2024, 11, 15, 10, 0, 0, 0, time.UTC)
+	history := []roundRecord{
+		{round: 1, attempt: 1, start: start, end: start.Add(42 * time.Second)},
+		{round: 1, attempt: 2, start: start, end: start.Add(68 * time.Second)},
+		{round: 2, attempt: 1, start: start, end: start, story: "PROJ-2", skipped: true},
+	}
+
+	tests := []struct {
+		name    string
+		history []roundRecord
+		rounds  int
+		players int
+		want    string
+	}{
+		{"rounds", history, 1, 4, "Session summary: rounds 1, players 4, average round 0:55"},
+		{"no rounds", nil, 0, 1, "Session summary: rounds 0, players 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionSummary(tt.history, tt.rounds, tt.players); got != tt.want {
+				t.Errorf("sessionSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// A re-vote doesn't count as a round of its own
+	state = newGameState()
+	addPlayer("alice", nil)
+	castVote("alice", "5")
+	revealVotes()
+	revote()
+	castVote("alice", "8")
+	revealVotes()
+
+	state.mu.RLock()
+	got := sessionSummary(state.history, state.roundsPlayed, len(state.joined))
+	state.mu.RUnlock()
+	if !strings.HasPrefix(got, "Session summary: rounds 1, players 1") {
+		t.Errorf("sessionSummary() after a re-vote = %q, want rounds 1, players 1", got)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// hostKeyPaths collects the host key files given with the repeatable
+// -host-key flag. It implements the flag.Value interface.
+type hostKeyPaths []string
+
+// String returns the configured paths as a comma-separated list.
+func (p *hostKeyPaths) String() string {
+	return strings.Join(*p, ",")
+}
+
+// Set adds one or more comma-separated paths, skipping empty entries.
+func (p *hostKeyPaths) Set(value string) error {
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		*p = append(*p, path)
+	}
+	return nil
+}
+
+// validateHostKeyPath checks that a host key path is usable. A missing file is
+// fine, as a new key is generated for it on startup.
+func validateHostKeyPath(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to access host key %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("host key %s is not a regular file", path)
+	}
+	return nil
+}
+
+// ensureHostKey generates an ed25519 host key at path when there's no file
+// yet, creating its directory as needed, and writes it readable by the owner
+// only. It returns the fingerprint of a generated key, or an empty string when
+// the key already exists.
+func ensureHostKey(path string) (string, error) {
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate host key: %w", err)
+	}
+	block, err := gossh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode host key: %w", err)
+	}
+	publicKey, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode host key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create host key directory: %w", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write host key %s: %w", path, err)
+	}
+	if err := os.WriteFile(path+".pub", gossh.MarshalAuthorizedKey(publicKey), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write host key %s.pub: %w", path, err)
+	}
+	return gossh.FingerprintSHA256(publicKey), nil
+}
+
+// readHostKey returns the public key of the host key at path.
+func readHostKey(path string) (gossh.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read host key: %w", err)
+	}
+	signer, err := gossh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse host key %s: %w", path, err)
+	}
+	return signer.PublicKey(), nil
+}
+
+// logHostKeys logs the type and fingerprint of each active host key. SSH
+// servers use a single key per algorithm, so when several keys share a type
+// only the last one is offered to clients.
+func logHostKeys(paths []string) {
+	seen := make(map[string]string)
+	for _, path := range paths {
+		key, err := readHostKey(path)
+		if err != nil {
+			log.Error("failed to load host key", "error", err, "path", path)
+			continue
+		}
+
+		keyType := key.Type()
+		if previous, ok := seen[keyType]; ok {
+			log.Warn("host key replaces another key of the same type", "path", path, "replaced", previous, "type", keyType)
+		}
+		seen[keyType] = path
+
+		log.Info("Host key active", "path", path, "type", keyType, "fingerprint", gossh.FingerprintSHA256(key))
+	}
+}
+
+// fingerprintText formats the fingerprint of a host key the way ssh shows it
+// when asked to trust a new server, like "ED25519 key fingerprint is
+// SHA256:...", so players can compare it.
+func fingerprintText(key gossh.PublicKey) string {
+	keyType := strings.TrimPrefix(key.Type(), "ssh-")
+	if strings.HasPrefix(keyType, "ecdsa") {
+		keyType = "ecdsa"
+	}
+	return fmt.Sprintf("%s key fingerprint is %s", strings.ToUpper(keyType), gossh.FingerprintSHA256(key))
+}
+
+// fingerprintBanner renders the fingerprint of a new host key prominently, for
+// the operator to share with the players to pin the server.
+func fingerprintBanner(key gossh.PublicKey) string {
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Render("New host key, share its fingerprint with the players:\n\n" +
+			fingerprintText(key) + "\n\n" +
+			"They compare it when ssh asks to trust the server on first connect.")
+}
+
+// printFingerprints writes the SHA256 fingerprint of each host key to w, one
+// per line, generating missing keys first so they can be shared before the
+// first start.
+func printFingerprints(paths []string, w io.Writer) error {
+	for _, path := range paths {
+		if _, err := ensureHostKey(path); err != nil {
+			return err
+		}
+		key, err := readHostKey(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, gossh.FingerprintSHA256(key))
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// TestHostKeyPathsFlag tests parsing of the repeatable -host-key flag
+func TestHostKeyPathsFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "no flag",
+			args: nil,
+			want: nil,
+		},
+		{
+			name: "single path",
+			args: []string{"-host-key", "a_ed25519"},
+			want: []string{"a_ed25519"},
+		},
+		{
+			name: "repeated flag",
+			args: []string{"-host-key", "a_ed25519", "-host-key", "b_rsa"},
+			want: []string{"a_ed25519", "b_rsa"},
+		},
+		{
+			name: "comma-separated with empty entries",
+			args: []string{"-host-key", "a_ed25519, ,b_rsa,"},
+			want: []string{"a_ed25519", "b_rsa"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths hostKeyPaths
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.Var(&paths, "host-key", "host key path")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() err = %v", err)
+			}
+
+			if len(paths) != len(tt.want) {
+				t.Fatalf("paths = %v, want %v", paths, tt.want)
+			}
+			for i := range tt.want {
+				if paths[i] != tt.want[i] {
+					t.Errorf("paths[%d] = %s, want %s", i, paths[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestValidateHostKeyPath tests validation of host key paths
+func TestValidateHostKeyPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "key")
+	if err := os.WriteFile(file, []byte("key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := validateHostKeyPath(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("validateHostKeyPath() on missing file err = %v, want nil", err)
+	}
+	if err := validateHostKeyPath(file); err != nil {
+		t.Errorf("validateHostKeyPath() on file err = %v, want nil", err)
+	}
+	if err := validateHostKeyPath(dir); err == nil {
+		t.Errorf("validateHostKeyPath() on directory err = nil, want error")
+	}
+}
+
+// TestEnsureHostKey tests generating a missing host key and keeping an
+// existing one
+func TestEnsureHostKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".ssh", "showdown_ed25519")
+
+	fingerprint, err := ensureHostKey(path)
+	if err != nil {
+		t.Fatalf("ensureHostKey() err = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("host key not written: %v", err)
+	}
+	signer, err := gossh.ParsePrivateKey(data)
+	if err != nil {
+		t.Fatalf("generated host key doesn't parse: %v", err)
+	}
+	if got := gossh.FingerprintSHA256(signer.PublicKey()); got != fingerprint {
+		t.Errorf("fingerprint = %s, want %s", fingerprint, got)
+	}
+	if signer.PublicKey().Type() != gossh.KeyAlgoED25519 {
+		t.Errorf("key type = %s, want %s", signer.PublicKey().Type(), gossh.KeyAlgoED25519)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("host key permissions = %o, want 600", perm)
+	}
+
+	// An existing key is kept
+	fingerprint, err = ensureHostKey(path)
+	if err != nil || fingerprint != "" {
+		t.Errorf("ensureHostKey() on existing key = %q, %v, want no new key", fingerprint, err)
+	}
+	if again, _ := os.ReadFile(path); string(again) != string(data) {
+		t.Errorf("existing host key was replaced")
+	}
+}
+
+// TestFingerprintText tests formatting the fingerprint of a known key the way
+// ssh shows it
+func TestFingerprintText(t *testing.T) {
+	key, _, _, _, err := gossh.ParseAuthorizedKey([]byte("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAABAgMEBQYHCAkKCwwNDg8QERITFBUWFxgZGhscHR4f"))
+	if err != nil {
+		t.Fatalf("failed to parse key: %v", err)
+	}
+
+	want := "ED25519 key fingerprint is SHA256:ZkAslGjFiUHdGf/WUL8rQvkib4PTvQatUV0OUQSncCA"
+	if got := fingerprintText(key); got != want {
+		t.Errorf("fingerprintText() = %q, want %q", got, want)
+	}
+	if banner := fingerprintBanner(key); !strings.Contains(banner, want) {
+		t.Errorf("fingerprintBanner() lacks the fingerprint:\n%s", banner)
+	}
+}
+
+// TestPrintFingerprints tests that missing host keys are generated and their
+// fingerprints printed one per line
+func TestPrintFingerprints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "showdown_ed25519")
+
+	var out bytes.Buffer
+	if err := printFingerprints([]string{path}, &out); err != nil {
+		t.Fatalf("printFingerprints() err = %v", err)
+	}
+	key, err := readHostKey(path)
+	if err != nil {
+		t.Fatalf("readHostKey() err = %v", err)
+	}
+	if want := gossh.FingerprintSHA256(key) + "\n"; out.String() != want {
+		t.Errorf("printFingerprints() output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// catalog maps the keys of the user-facing strings of the terminal views to
+// their text in one language. Texts are fmt formats when the lookup passes
+// arguments. Plural forms use the ".one" and ".other" suffixes of a key.
+type catalog map[string]string
+
+// catalogs holds the message catalog of each language selectable with -lang.
+// English is complete and the fallback for keys missing in other catalogs.
+var catalogs = map[string]catalog{
+	"en": {
+		"name.welcome":     "Welcome to Showdown!",
+		"name.placeholder": "Enter your name",
+		"name.continue":    "Press Enter to continue",
+		"name.error":       "Error: %s",
+		"join.full.one":    "game is full (maximum %d player)",
+		"join.full.other":  "game is full (maximum %d players)",
+
+		"player.title":       "🎲 Showdown - Player: %s",
+		"player.list":        "Select Points",
+		"player.riskList":    "Select Risk",
+		"player.risk":        "Risk: %s",
+		"player.cards.one":   "card",
+		"player.cards.other": "cards",
+		"player.ready":       "✅ Ready",
+		"player.closed":      "🔒 Voting closed",
+		"player.waitShare":   "🙈 Waiting for the Scrum Master to share the results",
+		"player.selected":    "Selected: %s",
+		"player.recorded":    "✓ Vote recorded",
+		"player.sealed":      "🔒 Vote sealed, open it after the reveal with: open <card> <nonce>",
+		"player.footer":      "Press a card's key to vote, %s to toggle ready, %s to set confidence, %s to react, %s to chat, %s for help, %s to quit",
+		"player.chatHint":    "Press Enter to send, Esc to cancel",
+		"player.confirmQuit": "Quit? votes will be lost (y/n)",
+		"player.idle":        "You'll be disconnected soon — press any key",
+		"player.roster":      "👥 Also here: %s",
+		"player.alone":       "👥 Nobody else has joined yet",
+		"player.results":     "📊 Voting Results:",
+		"player.votes":       "Player Votes:",
+		"player.noVote":      "no vote",
+
+		"master.title":          "🎲 Showdown - Scrum Master",
+		"master.facilitator":    "🎲 Showdown - Facilitator",
+		"master.round":          "Round %d.%d  Rounds: %d",
+		"master.locked":         "🔒 Joins are locked",
+		"master.waitingPlayers": "Waiting for players to join...",
+		"master.connected":      "Connected Players: %d",
+		"master.ready":          "⏳ %d/%d ready",
+		"master.players":        "Players:",
+		"master.shownEarly":     "(shown early)",
+		"master.waiting":        "waiting...",
+		"master.blind":          "🙈 Only you can see the votes, press R to share them",
+		"master.progress":       "Voting Progress: %d/%d",
+		"master.tally":          "📊 Live tally: %s",
+		"master.waitingOn":      "⏳ Waiting on: %s",
+		"master.capacity":       "Players: %d/%d",
+		"master.revealing":      "Revealing...",
+		"master.copied":         "Results printed above, press any key to return",
+
+		"master.announceCleared": "Announcement cleared",
+		"master.announceSent":    "Announcement sent",
+		"master.announcePrompt":  "Announce: ",
+		"master.whisperPrompt":   "Whisper to %s: ",
+		"master.whispered":       "Whispered to %s",
+		"master.whisperFailed":   "Could not whisper to %s",
+		"master.joinsLocked":     "Joins are locked",
+		"master.joinsUnlocked":   "Joins are unlocked",
+		"master.deck":            "Deck: %s (%s)",
+		"master.exportHidden":    "Reveal the votes before exporting",
+		"master.exportFailed":    "Export failed: %v",
+		"master.exported":        "Exported results to %s",
+		"master.copyHidden":      "Reveal the votes before copying",
+		"master.waitReady":       "Waiting for everyone to be ready",
+		"master.timeUpFewVotes":  "Revealed at time's up with fewer than %d votes",
+		"master.offline":         "(offline)",
+		"master.version":         "Showdown %s • up %s",
+		"master.requeued.one":    "Re-queued %d skipped story",
+		"master.requeued.other":  "Re-queued %d skipped stories",
+
+		"master.needVotes.one":   "Need %d more vote to reveal",
+		"master.needVotes.other": "Need %d more votes to reveal",
+
+		"vote.confidence":       "(%s confidence)",
+		"vote.abstained":        "(abstained)",
+		"vote.risk":             "(risk %s)",
+		"stats.effort":          "Effort",
+		"stats.risk":            "Risk",
+		"stats.noVotes":         "No votes",
+		"confidence.low":        "low",
+		"confidence.med":        "med",
+		"confidence.high":       "high",
+		"confidence.title":      "🎯 Confidence: %s",
+		"stats.title":           "📊 Voting Statistics:",
+		"stats.average":         "Average: %s",
+		"stats.trimmed":         "Trimmed avg: %s",
+		"stats.suggested":       "Suggested: %s",
+		"stats.median":          "Median: %s",
+		"stats.distribution":    "Distribution:",
+		"stats.wideSpread":      "⚠ Wide spread — discuss before accepting",
+		"stats.votes.one":       "%d vote",
+		"stats.votes.other":     "%d votes",
+		"stats.percentOf.one":   "(%.1f%% of %d player)",
+		"stats.percentOf.other": "(%.1f%% of %d players)",
+		"stats.roundTiming":     "Round took %s  Typical round: %s",
+		"stats.fastest":         "⚡ Fastest: %s (%ds)",
+
+		"timer.voting":     "⏱  Timer",
+		"timer.discussion": "💬 Discussion",
+		"timer.revealNow":  "⏰ Time's up — reveal now",
+		"timer.up":         "%s: Time's up!",
+
+		"story.complete":        "📝 Backlog complete",
+		"story.completeSkipped": "📝 Backlog complete, %d skipped (press . to re-queue)",
+		"chat.title":            "💬 Chat:",
+		"activity.title":        "Activity:",
+	},
+	"de": {
+		"name.welcome":     "Willkommen bei Showdown!",
+		"name.placeholder": "Gib deinen Namen ein",
+		"name.continue":    "Enter drücken, um fortzufahren",
+		"name.error":       "Fehler: %s",
+		"join.full.one":    "das Spiel ist voll (höchstens %d Spieler)",
+		"join.full.other":  "das Spiel ist voll (höchstens %d Spieler)",
+
+		"player.title":       "🎲 Showdown - Spieler: %s",
+		"player.list":        "Punkte wählen",
+		"player.riskList":    "Risiko wählen",
+		"player.risk":        "Risiko: %s",
+		"player.cards.one":   "Karte",
+		"player.cards.other": "Karten",
+		"player.ready":       "✅ Bereit",
+		"player.closed":      "🔒 Abstimmung geschlossen",
+		"player.waitShare":   "🙈 Warte, bis der Scrum Master die Ergebnisse teilt",
+		"player.selected":    "Gewählt: %s",
+		"player.recorded":    "✓ Stimme erfasst",
+		"player.sealed":      "🔒 Stimme versiegelt, nach dem Aufdecken öffnen mit: open <Karte> <Nonce>",
+		"player.footer":      "Taste einer Karte zum Abstimmen, %s für bereit, %s für Sicherheit, %s zum Reagieren, %s zum Chatten, %s für Hilfe, %s zum Beenden",
+		"player.chatHint":    "Enter zum Senden, Esc zum Abbrechen",
+		"player.confirmQuit": "Beenden? Die Stimme geht verloren (y/n)",
+		"player.idle":        "Du wirst bald getrennt — drücke eine beliebige Taste",
+		"player.roster":      "👥 Auch da: %s",
+		"player.alone":       "👥 Noch ist niemand sonst da",
+		"player.results":     "📊 Abstimmungsergebnis:",
+		"player.votes":       "Stimmen:",
+		"player.noVote":      "keine Stimme",
+
+		"master.title":          "🎲 Showdown - Scrum Master",
+		"master.facilitator":    "🎲 Showdown - Moderation",
+		"master.round":          "Runde %d.%d  Runden: %d",
+		"master.locked":         "🔒 Beitritte sind gesperrt",
+		"master.waitingPlayers": "Warte auf Spieler...",
+		"master.connected":      "Verbundene Spieler: %d",
+		"master.ready":          "⏳ %d/%d bereit",
+		"master.players":        "Spieler:",
+		"master.shownEarly":     "(vorab gezeigt)",
+		"master.waiting":        "wartet...",
+		"master.blind":          "🙈 Nur du siehst die Stimmen, R teilt sie",
+		"master.progress":       "Abgestimmt: %d/%d",
+		"master.tally":          "📊 Zwischenstand: %s",
+		"master.waitingOn":      "⏳ Warte auf: %s",
+		"master.capacity":       "Spieler: %d/%d",
+		"master.revealing":      "Aufdecken...",
+		"master.copied":         "Ergebnisse oben ausgegeben, beliebige Taste kehrt zurück",
+
+		"master.announceCleared": "Ankündigung entfernt",
+		"master.announceSent":    "Ankündigung gesendet",
+		"master.announcePrompt":  "Ankündigen: ",
+		"master.whisperPrompt":   "Flüstern an %s: ",
+		"master.whispered":       "Geflüstert an %s",
+		"master.whisperFailed":   "Flüstern an %s fehlgeschlagen",
+		"master.joinsLocked":     "Beitritte sind gesperrt",
+		"master.joinsUnlocked":   "Beitritte sind freigegeben",
+		"master.deck":            "Deck: %s (%s)",
+		"master.exportHidden":    "Vor dem Exportieren die Stimmen aufdecken",
+		"master.exportFailed":    "Export fehlgeschlagen: %v",
+		"master.exported":        "Ergebnisse exportiert nach %s",
+		"master.copyHidden":      "Vor dem Kopieren die Stimmen aufdecken",
+		"master.waitReady":       "Warte, bis alle bereit sind",
+		"master.timeUpFewVotes":  "Bei Zeitablauf mit weniger als %d Stimmen aufgedeckt",
+		"master.offline":         "(offline)",
+		"master.version":         "Showdown %s • läuft seit %s",
+		"master.requeued.one":    "%d übersprungene Story wieder eingereiht",
+		"master.requeued.other":  "%d übersprungene Stories wieder eingereiht",
+
+		"master.needVotes.one":   "Noch %d Stimme zum Aufdecken nötig",
+		"master.needVotes.other": "Noch %d Stimmen zum Aufdecken nötig",
+
+		"vote.confidence":       "(Sicherheit: %s)",
+		"vote.abstained":        "(enthalten)",
+		"vote.risk":             "(Risiko %s)",
+		"stats.effort":          "Aufwand",
+		"stats.risk":            "Risiko",
+		"stats.noVotes":         "Keine Stimmen",
+		"confidence.low":        "niedrig",
+		"confidence.med":        "mittel",
+		"confidence.high":       "hoch",
+		"confidence.title":      "🎯 Sicherheit: %s",
+		"stats.title":           "📊 Statistik:",
+		"stats.average":         "Durchschnitt: %s",
+		"stats.trimmed":         "Getrimmter Durchschnitt: %s",
+		"stats.suggested":       "Vorschlag: %s",
+		"stats.median":          "Median: %s",
+		"stats.distribution":    "Verteilung:",
+		"stats.wideSpread":      "⚠ Große Streuung — vor dem Übernehmen besprechen",
+		"stats.votes.one":       "%d Stimme",
+		"stats.votes.other":     "%d Stimmen",
+		"stats.percentOf.one":   "(%.1f%% von %d Spieler)",
+		"stats.percentOf.other": "(%.1f%% von %d Spielern)",
+		"stats.roundTiming":     "Runde dauerte %s  Typische Runde: %s",
+		"stats.fastest":         "⚡ Am schnellsten: %s (%ds)",
+
+		"timer.voting":     "⏱  Timer",
+		"timer.discussion": "💬 Diskussion",
+		"timer.revealNow":  "⏰ Zeit abgelaufen — jetzt aufdecken",
+		"timer.up":         "%s: Zeit abgelaufen!",
+
+		"story.complete":        "📝 Backlog erledigt",
+		"story.completeSkipped": "📝 Backlog erledigt, %d übersprungen (. stellt sie wieder ein)",
+		"chat.title":            "💬 Chat:",
+		"activity.title":        "Aktivität:",
+	},
+}
+
+// checkLanguage returns an error for a language without a catalog.
+func checkLanguage(code string) error {
+	if _, ok := catalogs[code]; !ok {
+		return fmt.Errorf("unsupported language %q (available: %s)", code, strings.Join(languages(), ", "))
+	}
+	return nil
+}
+
+// languages returns the codes of the available languages, sorted.
+func languages() []string {
+	return slices.Sorted(maps.Keys(catalogs))
+}
+
+// t returns the text of key in the selected language, falling back to English
+// and then to the key itself. With arguments the text is used as fmt format.
+func t(key string, args ...any) string {
+	return translate(options.Lang, key, args...)
+}
+
+// translate returns the text of key in the given language, like t.
+func translate(code, key string, args ...any) string {
+	text, ok := catalogs[code][key]
+	if !ok {
+		text, ok = catalogs["en"][key]
+	}
+	if !ok {
+		text = key
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// plural returns the singular or plural text of key for the count n in the
+// selected language, formatted with n.
+func plural(key string, n int) string {
+	if n == 1 {
+		return t(key+".one", n)
+	}
+	return t(key+".other", n)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestCatalogsComplete tests that every language translates every key of the
+// English catalog and no more
+func TestCatalogsComplete(t *testing.T) {
+	for code, messages := range catalogs {
+		for key := range catalogs["en"] {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("catalog %s is missing %q", code, key)
+			}
+		}
+		for key := range messages {
+			if _, ok := catalogs["en"][key]; !ok {
+				t.Errorf("catalog %s has unknown key %q", code, key)
+			}
+		}
+	}
+}
+
+// TestCatalogsCoverSources tests that every key looked up with t or plural in
+// the sources is in the English catalog, so no view falls back to the key
+func TestCatalogsCoverSources(t *testing.T) {
+	lookup := regexp.MustCompile(`\b(t|plural)\("([a-zA-Z.]+)"[,)]`)
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range lookup.FindAllStringSubmatch(string(src), -1) {
+			keys := []string{match[2]}
+			if match[1] == "plural" {
+				keys = []string{match[2] + ".one", match[2] + ".other"}
+			}
+			for _, key := range keys {
+				if _, ok := catalogs["en"][key]; !ok {
+					t.Errorf("%s looks up %q, which isn't in the catalog", file, key)
+				}
+			}
+		}
+	}
+}
+
+// TestMasterStatusTranslated tests that the status lines, prompts, and markers
+// of the master view follow the language
+func TestMasterStatusTranslated(t *testing.T) {
+	defer func() { options.Lang = "en" }()
+	options.Lang = "de"
+	state = newGameState()
+	addPlayer("alice", nil)
+	state.players["alice"].offline = true
+
+	var model tea.Model = newMasterView()
+	model, _ = model.Update(keyRunes("l"))
+	view := model.View()
+	for _, want := range []string{"Beitritte sind gesperrt", "alice (offline)", "läuft seit"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("master view in de doesn't contain %q:\n%s", want, view)
+		}
+	}
+	for _, english := range []string{"Joins are locked", "• up "} {
+		if strings.Contains(view, english) {
+			t.Errorf("master view in de still contains %q:\n%s", english, view)
+		}
+	}
+}
+
+// TestCheckLanguage tests accepting only languages with a catalog
+func TestCheckLanguage(t *testing.T) {
+	tests := []struct {
+		code    string
+		wantErr bool
+	}{
+		{"en", false},
+		{"de", false},
+		{"xx", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		err := checkLanguage(tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkLanguage(%q) err = %v, wantErr %v", tt.code, err, tt.wantErr)
+		}
+	}
+}
+
+// TestPlural tests picking the singular or plural form by count
+func TestPlural(t *testing.T) {
+	defer func() { options.Lang = "en" }()
+
+	tests := []struct {
+		lang  string
+		count int
+		want  string
+	}{
+		{"en", 0, "0 votes"},
+		{"en", 1, "1 vote"},
+		{"en", 3, "3 votes"},
+		{"de", 1, "1 Stimme"},
+		{"de", 2, "2 Stimmen"},
+	}
+
+	for _, tt := range tests {
+		options.Lang = tt.lang
+		if got := plural("stats.votes", tt.count); got != tt.want {
+			t.Errorf("plural(stats.votes, %d) in %s = %q, want %q", tt.count, tt.lang, got, tt.want)
+		}
+	}
+}
+
+// TestLanguageViews tests that switching the language changes the rendered
+// master, player, and statistics views
+func TestLanguageViews(t *testing.T) {
+	defer func() { options.Lang = "en" }()
+
+	tests := []struct {
+		lang       string
+		wantMaster []string
+		wantPlayer []string
+		wantStats  []string
+	}{
+		{
+			lang:       "en",
+			wantMaster: []string{"Connected Players: 2", "bob: waiting...", "Voting Progress: 1/2"},
+			wantPlayer: []string{"Player: alice", "Selected: 5", "👥 Also here: bob"},
+			wantStats:  []string{"Voting Statistics", "Average: 5.0", "1 vote "},
+		},
+		{
+			lang:       "de",
+			wantMaster: []string{"Verbundene Spieler: 2", "bob: wartet...", "Abgestimmt: 1/2"},
+			wantPlayer: []string{"Spieler: alice", "Gewählt: 5", "👥 Auch da: bob"},
+			wantStats:  []string{"Statistik", "Durchschnitt: 5.0", "1 Stimme "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			options.Lang = tt.lang
+			state = newGameState()
+			player, _ := initPlayerView("alice", nil)
+			addPlayer("bob", nil)
+			player, _ = player.Update(keyRunes("5"))
+
+			views := []struct {
+				name string
+				view string
+				want []string
+			}{
+				{"master", newMasterView().View(), tt.wantMaster},
+				{"player", player.View(), tt.wantPlayer},
+				{"statistics", showFinalVotes([]string{"5"}, 1, 1), tt.wantStats},
+			}
+			for _, v := range views {
+				for _, want := range v.want {
+					if !strings.Contains(v.view, want) {
+						t.Errorf("%s view in %s doesn't contain %q:\n%s", v.name, tt.lang, want, v.view)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// jiraPageSize is how many issues are requested per Jira search page.
+const jiraPageSize = 50
+
+// jiraClient imports the backlog from Jira and writes estimates back to the
+// story point field of its issues.
+type jiraClient struct {
+	baseURL string
+	token   string
+	user    string
+	field   string
+	http    httpClient
+}
+
+// jiraSearchResponse is the part of a Jira search response the backlog uses.
+type jiraSearchResponse struct {
+	StartAt    int `json:"startAt"`
+	MaxResults int `json:"maxResults"`
+	Total      int `json:"total"`
+	Issues     []struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+		} `json:"fields"`
+	} `json:"issues"`
+}
+
+// newJiraClient returns a Jira client for the instance at baseURL. With a user
+// the token is sent with basic auth, as Jira Cloud expects for API tokens,
+// otherwise as a bearer personal access token. Estimates are written to the
+// given story point field, like customfield_10016.
+func newJiraClient(baseURL, token, user, field string, client httpClient) *jiraClient {
+	return &jiraClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		user:    user,
+		field:   field,
+		http:    client,
+	}
+}
+
+// parseJiraIssues decodes one page of a Jira search response into stories. It
+// also returns the total number of matching issues.
+func parseJiraIssues(r io.Reader) ([]Story, int, error) {
+	var resp jiraSearchResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse Jira search response: %w", err)
+	}
+
+	stories := make([]Story, 0, len(resp.Issues))
+	for _, issue := range resp.Issues {
+		stories = append(stories, Story{ID: issue.Key, Title: issue.Fields.Summary})
+	}
+	return stories, resp.Total, nil
+}
+
+// do sends an authenticated request to the Jira REST API and returns the
+// response body, failing on any status other than want.
+func (c *jiraClient) do(method, path string, body io.Reader, want int) ([]byte, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.token)
+	} else if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != want {
+		return nil, fmt.Errorf("jira %s %s: %s", method, path, resp.Status)
+	}
+	return data, nil
+}
+
+// searchStories returns the issues matching the JQL query as stories, fetching
+// all result pages.
+func (c *jiraClient) searchStories(jql string) ([]Story, error) {
+	var stories []Story
+	for {
+		query := url.Values{
+			"jql":        {jql},
+			"fields":     {"summary"},
+			"startAt":    {strconv.Itoa(len(stories))},
+			"maxResults": {strconv.Itoa(jiraPageSize)},
+		}
+		data, err := c.do(http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+
+		page, total, err := parseJiraIssues(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, page...)
+		if len(page) == 0 || len(stories) >= total {
+			return stories, nil
+		}
+	}
+}
+
+// writeEstimate sets the story point field of the story's issue to the
+// estimate.
+func (c *jiraClient) writeEstimate(story Story, estimate string) error {
+	points, err := strconv.ParseFloat(estimate, 64)
+	if err != nil {
+		return fmt.Errorf("estimate %q is not a number", estimate)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"fields": map[string]float64{c.field: points},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.do(http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(story.ID), bytes.NewReader(body), http.StatusNoContent)
+	return err
+}
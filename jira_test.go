@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// stubHTTPClient answers requests with canned responses and records them.
+type stubHTTPClient struct {
+	responses []*http.Response
+	requests  []*http.Request
+	bodies    []string
+}
+
+// Do records the request and returns the next canned response.
+func (c *stubHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	body := ""
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+	}
+	c.bodies = append(c.bodies, body)
+
+	resp := c.responses[0]
+	c.responses = c.responses[1:]
+	return resp, nil
+}
+
+// stubResponse returns an HTTP response with the given status and body.
+func stubResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// TestParseJiraIssues tests decoding the Jira search response shape
+func TestParseJiraIssues(t *testing.T) {
+	payload := `{
+		"startAt": 0,
+		"maxResults": 50,
+		"total": 2,
+		"issues": [
+			{"id": "10001", "key": "PROJ-1", "fields": {"summary": "Fix login"}},
+			{"id": "10002", "key": "PROJ-2", "fields": {"summary": "Add dark mode", "status": {"name": "To Do"}}}
+		]
+	}`
+
+	stories, total, err := parseJiraIssues(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("parseJiraIssues() err = %v", err)
+	}
+	want := []Story{{ID: "PROJ-1", Title: "Fix login"}, {ID: "PROJ-2", Title: "Add dark mode"}}
+	if !slices.Equal(stories, want) || total != 2 {
+		t.Errorf("parseJiraIssues() = %v, %d, want %v, 2", stories, total, want)
+	}
+
+	if _, _, err := parseJiraIssues(strings.NewReader("<html>")); err == nil {
+		t.Errorf("parseJiraIssues() of HTML err = nil, want error")
+	}
+}
+
+// TestJiraSearchStoriesPages tests fetching all pages of a Jira search
+func TestJiraSearchStoriesPages(t *testing.T) {
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"total": 2, "issues": [{"key": "PROJ-1", "fields": {"summary": "Fix login"}}]}`),
+		stubResponse(http.StatusOK, `{"total": 2, "issues": [{"key": "PROJ-2", "fields": {"summary": "Add dark mode"}}]}`),
+	}}
+	client := newJiraClient("https://jira.example.com/", "secret", "", "customfield_10016", stub)
+
+	stories, err := client.searchStories("project = PROJ")
+	if err != nil {
+		t.Fatalf("searchStories() err = %v", err)
+	}
+	if len(stories) != 2 || stories[1].ID != "PROJ-2" {
+		t.Errorf("searchStories() = %v, want PROJ-1 and PROJ-2", stories)
+	}
+
+	req := stub.requests[1]
+	if req.URL.Path != "/rest/api/2/search" || req.URL.Query().Get("jql") != "project = PROJ" || req.URL.Query().Get("startAt") != "1" {
+		t.Errorf("second search request = %s", req.URL)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q, want bearer token", got)
+	}
+}
+
+// TestJiraWriteEstimate tests writing the estimate to the story point field
+func TestJiraWriteEstimate(t *testing.T) {
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusNoContent, ""),
+		stubResponse(http.StatusBadRequest, `{"errors": {}}`),
+	}}
+	client := newJiraClient("https://jira.example.com", "token", "robin@example.com", "customfield_10016", stub)
+
+	if err := client.writeEstimate(Story{ID: "PROJ-1"}, "5"); err != nil {
+		t.Fatalf("writeEstimate() err = %v", err)
+	}
+	req := stub.requests[0]
+	if req.Method != http.MethodPut || req.URL.Path != "/rest/api/2/issue/PROJ-1" {
+		t.Errorf("request = %s %s, want PUT /rest/api/2/issue/PROJ-1", req.Method, req.URL.Path)
+	}
+	if user, _, ok := req.BasicAuth(); !ok || user != "robin@example.com" {
+		t.Errorf("basic auth user = %q, want robin@example.com", user)
+	}
+	var body struct {
+		Fields map[string]float64 `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(stub.bodies[0]), &body); err != nil || body.Fields["customfield_10016"] != 5 {
+		t.Errorf("request body = %s, want customfield_10016 set to 5", stub.bodies[0])
+	}
+
+	if err := client.writeEstimate(Story{ID: "PROJ-2"}, "8"); err == nil {
+		t.Errorf("writeEstimate() with bad request err = nil, want error")
+	}
+	if err := client.writeEstimate(Story{ID: "PROJ-3"}, "?"); err == nil {
+		t.Errorf("writeEstimate() of ? err = nil, want error")
+	}
+}
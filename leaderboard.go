@@ -0,0 +1,57 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// fastestVoters returns the names of the players who voted fastest after the
+// round started, sorted by name, and their time rounded to the second. Players
+// without a vote time are skipped; it returns no names when nobody voted.
+func fastestVoters(delays map[string]time.Duration) ([]string, time.Duration) {
+	var (
+		names []string
+		best  time.Duration
+	)
+	for name, delay := range delays {
+		delay = delay.Round(time.Second)
+		switch {
+		case len(names) == 0 || delay < best:
+			names, best = []string{name}, delay
+		case delay == best:
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, best
+}
+
+// voteDelays returns how long each player who voted took after the round
+// started. Callers must hold state.mu.
+func voteDelays() map[string]time.Duration {
+	delays := make(map[string]time.Duration)
+	if state.roundStart.IsZero() {
+		return delays
+	}
+	for _, player := range state.players {
+		if vote := player.vote(); vote.selected && !vote.votedAt.IsZero() {
+			delays[player.name] = max(vote.votedAt.Sub(state.roundStart), 0)
+		}
+	}
+	return delays
+}
+
+// leaderboardView renders the fastest voter of the round, like
+// "⚡ Fastest: alice (3s)", when the leaderboard is enabled. Callers must hold
+// state.mu.
+func leaderboardView() string {
+	if !options.Leaderboard {
+		return ""
+	}
+	names, delay := fastestVoters(voteDelays())
+	if len(names) == 0 {
+		return ""
+	}
+	return t("stats.fastest", strings.Join(names, ", "), int(delay.Seconds())) + "\n"
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFastestVoters tests finding the fastest voters with ties and missing times
+func TestFastestVoters(t *testing.T) {
+	tests := []struct {
+		name      string
+		delays    map[string]time.Duration
+		wantNames []string
+		wantDelay time.Duration
+	}{
+		{
+			name:      "no votes",
+			delays:    map[string]time.Duration{},
+			wantNames: nil,
+		},
+		{
+			name:      "single fastest",
+			delays:    map[string]time.Duration{"alice": 3 * time.Second, "bob": 8 * time.Second},
+			wantNames: []string{"alice"},
+			wantDelay: 3 * time.Second,
+		},
+		{
+			name:      "tie within the same second",
+			delays:    map[string]time.Duration{"carol": 2200 * time.Millisecond, "bob": 1900 * time.Millisecond, "dave": 9 * time.Second},
+			wantNames: []string{"bob", "carol"},
+			wantDelay: 2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, delay := fastestVoters(tt.delays)
+			if !slices.Equal(names, tt.wantNames) || (len(names) > 0 && delay != tt.wantDelay) {
+				t.Errorf("fastestVoters() = %v, %v, want %v, %v", names, delay, tt.wantNames, tt.wantDelay)
+			}
+		})
+	}
+}
+
+// TestLeaderboardView tests that players without a vote are skipped and that
+// the leaderboard is opt-in
+func TestLeaderboardView(t *testing.T) {
+	defer func(show bool) { options.Leaderboard = show }(options.Leaderboard)
+
+	state = newGameState()
+	start := time.Now().Add(-time.Minute)
+	state.roundStart = start
+	state.putPlayer("alice", &playerState{name: "alice", selected: true, points: "5", votedAt: start.Add(12 * time.Second)})
+	state.putPlayer("bob", &playerState{name: "bob", selected: true, points: "3", votedAt: start.Add(4 * time.Second)})
+	state.putPlayer("carol", &playerState{name: "carol"})
+
+	options.Leaderboard = false
+	if got := leaderboardView(); got != "" {
+		t.Errorf("leaderboardView() when disabled = %q, want empty", got)
+	}
+
+	options.Leaderboard = true
+	if got := leaderboardView(); got != "⚡ Fastest: bob (4s)\n" {
+		t.Errorf("leaderboardView() = %q, want bob (4s)", got)
+	}
+
+	// A vote records its time once per round
+	castVote("carol", "8")
+	if got := state.players["carol"].votedAt; got.IsZero() {
+		t.Errorf("castVote() didn't record the vote time")
+	}
+	clearPlayerState()
+	if got := leaderboardView(); strings.Contains(got, "Fastest") {
+		t.Errorf("leaderboardView() after clearPlayerState() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// lineModeVote lets a client without a terminal, like a script or CI job, vote
+// in plain text: it prints the deck, reads a single line with the card, casts
+// the vote for the SSH user, and prints the result. The player stays in the
+// game as offline once the session closes, so the vote counts in the round.
+func lineModeVote(s ssh.Session) {
+	name := s.User()
+	if err := checkJoin(name, s); err != nil {
+		wish.Fatalf(s, "Cannot join as %q: %v\n", name, err)
+		return
+	}
+
+	deck := currentDeck()
+	fmt.Fprintf(s, "Showdown - Player: %s\nCards: %s\nVote: ", name, strings.Join(deck, " "))
+	line, err := bufio.NewReader(s).ReadString('\n')
+	if err != nil && line == "" {
+		log.Info("Line mode player left without voting", "player", name, "error", err)
+		return
+	}
+
+	card, err := parseLineVote(line, deck)
+	if err != nil {
+		wish.Fatalln(s, err)
+		return
+	}
+	joinAndVote(s, name, card)
+}
+
+// commandVote casts the vote of a one-shot command like
+// "ssh host vote 5 --name alice" and exits, for automation. The name defaults
+// to the SSH user.
+func commandVote(s ssh.Session) {
+	name, card, err := parseVoteCommand(s.Command(), s.User(), currentDeck())
+	if err != nil {
+		wish.Fatalln(s, err)
+		return
+	}
+	if err := checkJoin(name, s); err != nil {
+		wish.Fatalf(s, "Cannot join as %q: %v\n", name, err)
+		return
+	}
+	joinAndVote(s, name, card)
+}
+
+// joinAndVote adds the player of a non-interactive session to the game and
+// casts their vote, reporting the result on the session.
+func joinAndVote(s ssh.Session, name, card string) {
+	addPlayer(name, s)
+	if !castVote(name, card) {
+		wish.Fatalln(s, "Voting is closed")
+		return
+	}
+	fmt.Fprintf(s, "Voted %s as %s\n", card, name)
+}
+
+// parseVoteCommand parses the arguments of a "vote <card> [--name <name>]"
+// command, also accepting --name=<name>. Without a name the user is voting.
+// The name must be a valid player name and the card must be in the deck.
+func parseVoteCommand(args []string, user string, deck []string) (name, card string, err error) {
+	if len(args) == 0 || args[0] != "vote" {
+		return "", "", fmt.Errorf("usage: vote <card> [--name <name>]")
+	}
+
+	name, cards, err := parseNameArgs(args, user)
+	if err != nil {
+		return "", "", err
+	}
+	if len(cards) != 1 {
+		return "", "", fmt.Errorf("usage: vote <card> [--name <name>]")
+	}
+
+	card, err = parseLineVote(cards[0], deck)
+	if err != nil {
+		return "", "", err
+	}
+	return name, card, nil
+}
+
+// parseNameArgs splits the arguments of a one-shot command after its name into
+// the --name option, also accepted as --name=<name>, and the remaining
+// arguments. Without a name the user is voting. The name must be a valid
+// player name.
+func parseNameArgs(args []string, user string) (name string, rest []string, err error) {
+	name = user
+	for i := 1; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--name" || arg == "-name":
+			if i+1 == len(args) {
+				return "", nil, fmt.Errorf("%s needs a name", arg)
+			}
+			i++
+			name = args[i]
+		case strings.HasPrefix(arg, "--name="):
+			name = strings.TrimPrefix(arg, "--name=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if err := validatePlayerName(name); err != nil {
+		return "", nil, err
+	}
+	return name, rest, nil
+}
+
+// parseLineVote returns the card of a line mode vote, ignoring surrounding
+// whitespace. The card must be in the deck.
+func parseLineVote(line string, deck []string) (string, error) {
+	card := strings.TrimSpace(line)
+	if card == "" {
+		return "", fmt.Errorf("no card given, pick one of: %s", strings.Join(deck, " "))
+	}
+	for _, c := range deck {
+		if c == card {
+			return card, nil
+		}
+	}
+	return "", fmt.Errorf("%q is not a card, pick one of: %s", card, strings.Join(deck, " "))
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+// TestParseLineVote tests parsing and validating a line mode vote
+func TestParseLineVote(t *testing.T) {
+	deck := []string{"0.5", "1", "2", "3", "5", "?"}
+
+	tests := []struct {
+		name    string
+		line    string
+		want    string
+		wantErr bool
+	}{
+		{"card", "5\n", "5", false},
+		{"surrounding whitespace", "  0.5 \r\n", "0.5", false},
+		{"without newline", "?", "?", false},
+		{"empty line", "\n", "", true},
+		{"not in deck", "4\n", "", true},
+		{"partial card", "0.\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLineVote(tt.line, deck)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLineVote(%q) err = %v, wantErr %v", tt.line, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseLineVote(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseVoteCommand tests parsing and validating one-shot vote commands
+func TestParseVoteCommand(t *testing.T) {
+	deck := []string{"1", "2", "3", "5", "?"}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantName string
+		wantCard string
+		wantErr  bool
+	}{
+		{"name flag", []string{"vote", "5", "--name", "alice"}, "alice", "5", false},
+		{"name flag first", []string{"vote", "--name", "alice", "3"}, "alice", "3", false},
+		{"name flag with equals", []string{"vote", "?", "--name=bob"}, "bob", "?", false},
+		{"user as name", []string{"vote", "2"}, "ci", "2", false},
+		{"unknown command", []string{"reveal"}, "", "", true},
+		{"missing card", []string{"vote", "--name", "alice"}, "", "", true},
+		{"two cards", []string{"vote", "1", "2"}, "", "", true},
+		{"card not in deck", []string{"vote", "4", "--name", "alice"}, "", "", true},
+		{"missing name", []string{"vote", "5", "--name"}, "", "", true},
+		{"invalid name", []string{"vote", "5", "--name", "<script>"}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, card, err := parseVoteCommand(tt.args, "ci", deck)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseVoteCommand(%q) err = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if name != tt.wantName || card != tt.wantCard {
+				t.Errorf("parseVoteCommand(%q) = %q, %q, want %q, %q", tt.args, name, card, tt.wantName, tt.wantCard)
+			}
+		})
+	}
+}
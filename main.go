@@ -2,20 +2,24 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"flag"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"maps"
+	"math"
 	"net"
 	"os"
-	"os/signal"
 	"regexp"
 	"runtime/debug"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
-	"syscall"
 	"time"
 	"unicode"
 
@@ -26,22 +30,44 @@ import (
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/bubbletea"
-	"github.com/charmbracelet/wish/logging"
 	gossh "golang.org/x/crypto/ssh"
 )
 
 const (
 	shaLen = 7
 
+	// Default SSH server port for the -p flag
+	defaultPort = 23234
+
+	// Maximum number of decimals for the -precision flag
+	maxPrecision = 6
+
+	// Chart styles for the -chart flag and the width of the ASCII histogram
+	chartBars      = "bars"
+	chartASCII     = "ascii"
+	histogramWidth = 20
+
+	// What happens to a second session of a connected player, for the
+	// -duplicate-sessions flag
+	duplicateTakeover = "takeover"
+	duplicateReject   = "reject"
+
 	// Connection and resource limits
-	maxConnections      = 100
-	maxConnectionsPerIP = 10
-	maxPlayers          = 15
-	sessionTimeout      = 30 * time.Minute
-
-	// Player name validation
-	minNameLength = 2
-	maxNameLength = 20
+	defaultMaxConnections      = 100
+	defaultMaxConnectionsPerIP = 10
+	maxPlayers                 = 15
+	sessionTimeout             = 30 * time.Minute
+
+	// How long before the idle timeout players are warned
+	idleWarningBefore = 30 * time.Second
+
+	// Player name validation, with the default and the largest -name-limit
+	minNameLength    = 2
+	defaultNameLimit = 20
+	maxNameLimit     = 100
+
+	// Maximum length of messages typed by the Scrum Master
+	maxMessageLength = 100
 )
 
 // validNameRegex allows only alphanumeric characters, spaces, hyphens, and underscores
@@ -58,11 +84,17 @@ var (
 	CommitSHA = "unknown"
 )
 
+// Version string and start time of the running server, set in main() and
+// shown in the master view footer
+var (
+	serverVersion = Version
+	serverStart   = time.Now()
+)
+
 // Connection tracking for DoS protection
 var (
 	connectionCount atomic.Int32
 	connectionsByIP sync.Map // map[string]*atomic.Int32
-	reservedNames   = []string{"master", "admin", "system", "server", "scrum", "poker"}
 )
 
 // getConfigPath returns an absolute path for configuration files.
@@ -86,8 +118,8 @@ func validatePlayerName(name string) error {
 	if len(name) < minNameLength {
 		return fmt.Errorf("name must be at least %d characters", minNameLength)
 	}
-	if len(name) > maxNameLength {
-		return fmt.Errorf("name must be at most %d characters", maxNameLength)
+	if len(name) > options.NameLimit {
+		return fmt.Errorf("name must be at most %d characters", options.NameLimit)
 	}
 
 	// Check for valid characters only
@@ -102,29 +134,119 @@ func validatePlayerName(name string) error {
 		}
 	}
 
-	// Prevent reserved names
-	lowerName := strings.ToLower(name)
-	for _, reserved := range reservedNames {
-		if lowerName == reserved {
-			return fmt.Errorf("name '%s' is reserved", name)
-		}
+	// Require at least one letter or digit so names can't look empty
+	if !strings.ContainsFunc(name, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) {
+		return fmt.Errorf("name must contain at least one letter or number")
+	}
+
+	// Prevent reserved names, ignoring casing and repeated whitespace
+	if isReservedName(name) {
+		return fmt.Errorf("name '%s' is reserved", name)
 	}
 
 	return nil
 }
 
+// isReservedName reports whether name matches an entry in the reserved names,
+// ignoring casing and repeated whitespace.
+func isReservedName(name string) bool {
+	normalized := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	for _, reserved := range options.Reserved {
+		if normalized == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// parseReservedNames splits a comma-separated list of reserved names into
+// normalized entries, skipping empty ones.
+func parseReservedNames(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// playerKey normalizes a player name for use as a key in the players map, so
+// that names differing only in casing are treated as the same player.
+func playerKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// resetTerminalSequence exits the alternate screen buffer, shows the cursor,
+// resets the terminal to its initial state, and clears the screen.
+const resetTerminalSequence = "\033[?1049l" + // Exit alternate screen buffer
+	"\033[?25h" + // Show cursor
+	"\033c" + // Reset terminal to initial state
+	"\033[2J\033[H" // Clear screen and move cursor to home
+
 // resetTerminal sends ANSI escape sequences to reset the terminal state for a
-// given SSH session. It exits alternate screen buffer, shows the cursor, resets
-// terminal to initial state, and clears the screen.
-func resetTerminal(s ssh.Session) {
-	// Exit alternate screen buffer
-	s.Write([]byte("\033[?1049l"))
-	// Show cursor
-	s.Write([]byte("\033[?25h"))
-	// Reset terminal to initial state
-	s.Write([]byte("\033c"))
-	// Clear screen and move cursor to home
-	s.Write([]byte("\033[2J\033[H"))
+// given SSH session. The sequences are sent in a single write so they can't be
+// interleaved with the output of the session's program. It returns the write
+// error, which callers ignore when the session may already be closed.
+func resetTerminal(s ssh.Session) error {
+	_, err := s.Write([]byte(resetTerminalSequence))
+	return err
+}
+
+// disconnectSession resets the terminal of a player's SSH session and closes
+// it, logging when either fails so broken connections don't go unnoticed.
+func disconnectSession(name string, s ssh.Session) {
+	if err := resetTerminal(s); err != nil {
+		log.Warn("Could not reset player terminal", "player", name, "error", err)
+	}
+	if err := s.Close(); err != nil && !errors.Is(err, io.EOF) {
+		log.Warn("Could not close player session", "player", name, "error", err)
+	}
+}
+
+// resetSessions resets the terminals of the Scrum Masters and all players
+// connected over SSH, used on server shutdown.
+func resetSessions() {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	for s := range state.masters {
+		resetTerminal(s)
+	}
+	for _, player := range state.players {
+		if player.session != nil {
+			resetTerminal(player.session)
+		}
+	}
+}
+
+// syncSession is an SSH session whose writes are serialized, so terminal resets
+// from the shutdown and disconnect paths don't interleave with the output the
+// session's program is writing concurrently.
+type syncSession struct {
+	ssh.Session
+	mu sync.Mutex
+}
+
+// Write writes p to the session while holding the session's write lock.
+func (s *syncSession) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Session.Write(p)
+}
+
+// syncSessionMiddleware wraps every SSH session in a syncSession. It must be
+// the outermost middleware so the Bubble Tea program, the game state, and the
+// cleanup middlewares all write through the same lock.
+func syncSessionMiddleware() wish.Middleware {
+	return func(h ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			h(&syncSession{Session: s})
+		}
+	}
 }
 
 // Catppuccin Mocha colors
@@ -137,8 +259,40 @@ const (
 	catppuccinLavender = "#b4befe"
 	catppuccinCrust    = "#11111b"
 	catppuccinOverlay1 = "#7f849c"
+	catppuccinRed      = "#f38ba8"
+	catppuccinYellow   = "#f9e2af"
+	catppuccinGreen    = "#a6e3a1"
+	catppuccinTeal     = "#94e2d5"
+	catppuccinPink     = "#f5c2e7"
+	catppuccinFlamingo = "#f2cdcd"
+	catppuccinSapphire = "#74c7ec"
 )
 
+// playerColors is the palette used to give each player a stable name color
+// in the Scrum Master view.
+var playerColors = []lipgloss.Color{
+	catppuccinMauve,
+	catppuccinRed,
+	catppuccinPeach,
+	catppuccinYellow,
+	catppuccinGreen,
+	catppuccinTeal,
+	catppuccinSky,
+	catppuccinSapphire,
+	catppuccinBlue,
+	catppuccinLavender,
+	catppuccinPink,
+	catppuccinFlamingo,
+}
+
+// playerColor returns a deterministic color for the given player name, based
+// on a hash of its normalized form, so a player keeps the same color across rounds.
+func playerColor(name string) lipgloss.Color {
+	h := fnv.New32a()
+	h.Write([]byte(playerKey(name)))
+	return playerColors[h.Sum32()%uint32(len(playerColors))]
+}
+
 // Shared styles for statistics display
 var (
 	labelStyle = lipgloss.NewStyle().
@@ -153,6 +307,14 @@ var (
 	percentStyle = lipgloss.NewStyle().
 			Italic(true).
 			Foreground(lipgloss.Color(catppuccinSky))
+
+	timeUpStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(catppuccinRed))
+
+	spreadStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(catppuccinYellow))
 )
 
 // tickMsg represents a periodic tick message used for UI updates.
@@ -166,9 +328,75 @@ func tickEvery() tea.Cmd {
 	})
 }
 
+// timerKind distinguishes the voting timer, which reveals votes on expiry, from
+// the discussion timer, which only time-boxes the discussion after a reveal.
+type timerKind int
+
+const (
+	votingTimer timerKind = iota
+	discussionTimer
+)
+
+// timerView renders the countdown of the running timer for both master and
+// player views. It returns an empty string when no timer has been started.
+// Callers must hold state.mu.
+func timerView() string {
+	if state.timerEnd.IsZero() {
+		return ""
+	}
+
+	label := t("timer.voting")
+	if state.timerKind == discussionTimer {
+		label = t("timer.discussion")
+	}
+
+	remaining := time.Until(state.timerEnd)
+	if remaining <= 0 {
+		if state.timerKind == votingTimer {
+			return timeUpStyle.Render(t("timer.revealNow")) + "\n\n"
+		}
+		return t("timer.up", label) + "\n\n"
+	}
+	return fmt.Sprintf("%s: %02d:%02d\n\n", label,
+		int(remaining.Minutes()),
+		int(remaining.Seconds())%60)
+}
+
+// expiredTimer returns the end of the running timer once it has expired, or the
+// zero time while it is still running or none was started. Callers must hold
+// state.mu.
+func expiredTimer() time.Time {
+	if state.timerEnd.IsZero() || time.Now().Before(state.timerEnd) {
+		return time.Time{}
+	}
+	return state.timerEnd
+}
+
+// ringBell returns a command that rings the terminal bell of the session the
+// program renders to. Writing from the program's command keeps the bell on the
+// same synchronized session as the frames, so it never lands inside one. It
+// returns nil for web players without a session or when -no-bell is set.
+func ringBell(out io.Writer) tea.Cmd {
+	if out == nil || options.NoBell {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, err := io.WriteString(out, "\a"); err != nil {
+			log.Error("Failed to ring the terminal bell", "error", err)
+		}
+		return nil
+	}
+}
+
 // calculateStatistics computes voting statistics from a slice of point values.
 // It returns the average (for numeric values), median, and a distribution map
 // showing how many times each point value was selected.
+//
+// For an even number of numeric votes the median is the mean of the two middle
+// votes by default. Decks aren't continuous, so the mean of 3 and 5 is 4.0,
+// which isn't a card anyone can pick. With -median-lower the lower of the
+// two middle votes is reported instead, which is always a card but hides that
+// the team was split between two estimates.
 func calculateStatistics(points []string) (float64, string, map[string]int) {
 	var numericPoints []float64
 	distribution := make(map[string]int)
@@ -176,7 +404,7 @@ func calculateStatistics(points []string) (float64, string, map[string]int) {
 	// Calculate distribution and collect numeric points
 	for _, p := range points {
 		distribution[p]++
-		if num, err := strconv.ParseFloat(p, 64); err == nil {
+		if num, ok := cardValue(p); ok {
 			numericPoints = append(numericPoints, num)
 		}
 	}
@@ -196,10 +424,12 @@ func calculateStatistics(points []string) (float64, string, map[string]int) {
 	if len(numericPoints) > 0 {
 		sort.Float64s(numericPoints)
 		mid := len(numericPoints) / 2
-		if len(numericPoints)%2 == 0 {
-			median = fmt.Sprintf("%.1f", (numericPoints[mid-1]+numericPoints[mid])/2)
+		if len(numericPoints)%2 == 0 && options.MedianLower {
+			median = formatStat(numericPoints[mid-1])
+		} else if len(numericPoints)%2 == 0 {
+			median = formatStat((numericPoints[mid-1] + numericPoints[mid]) / 2)
 		} else {
-			median = fmt.Sprintf("%.1f", numericPoints[mid])
+			median = formatStat(numericPoints[mid])
 		}
 	} else {
 		median = "N/A"
@@ -208,40 +438,195 @@ func calculateStatistics(points []string) (float64, string, map[string]int) {
 	return average, median, distribution
 }
 
+// trimmedMean returns the mean of the values without the highest and the lowest
+// value, so a single outlier doesn't skew the estimate. With fewer than three
+// values nothing is trimmed and the plain mean is returned, or 0 without
+// values.
+func trimmedMean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := slices.Sorted(slices.Values(values))
+	if len(sorted) >= 3 {
+		sorted = sorted[1 : len(sorted)-1]
+	}
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	return sum / float64(len(sorted))
+}
+
+// wideSpread reports whether the numeric votes disagree so much that the
+// round needs discussion: the highest vote is more than threshold times the
+// lowest. A threshold of 0 or less disables the check.
+func wideSpread(values []float64, threshold float64) bool {
+	if threshold <= 0 || len(values) < 2 {
+		return false
+	}
+	return slices.Max(values) > threshold*slices.Min(values)
+}
+
+// numericPoints returns the votes that are numbers, skipping values like "?".
+func numericPoints(points []string) []float64 {
+	var values []float64
+	for _, p := range points {
+		if num, ok := cardValue(p); ok {
+			values = append(values, num)
+		}
+	}
+	return values
+}
+
+// nearestCard snaps a numeric average to the closest numeric card of the deck.
+// Ties are rounded up to the larger card for conservative estimation. It
+// returns an empty string when the deck has no numeric cards.
+func nearestCard(avg float64, deck []string) string {
+	var (
+		best     string
+		bestVal  float64
+		bestDist = math.Inf(1)
+	)
+	for _, card := range deck {
+		val, ok := cardValue(card)
+		if !ok {
+			continue
+		}
+		dist := math.Abs(val - avg)
+		closer := dist < bestDist-1e-9
+		tie := math.Abs(dist-bestDist) <= 1e-9 && val > bestVal
+		if closer || tie {
+			best, bestVal, bestDist = card, val, dist
+		}
+	}
+	return best
+}
+
+// sortedPointValues returns the point values of a distribution sorted
+// numerically, followed by the non-numeric values such as "?".
+func sortedPointValues(distribution map[string]int) []string {
+	pointValues := make([]string, 0, len(distribution))
+	for p := range distribution {
+		pointValues = append(pointValues, p)
+	}
+	sort.Slice(pointValues, func(i, j int) bool {
+		a, okA := cardValue(pointValues[i])
+		b, okB := cardValue(pointValues[j])
+		switch {
+		case okA && okB:
+			return a < b
+		case okA || okB:
+			return okA
+		default:
+			return pointValues[i] < pointValues[j]
+		}
+	})
+	return pointValues
+}
+
+// renderHistogram renders the distribution as a plain text bar chart, one line
+// per point value like "5 |████████ 3", with bars scaled to the largest count
+// so it copies cleanly into notes.
+func renderHistogram(distribution map[string]int) string {
+	pointValues := sortedPointValues(distribution)
+
+	labelWidth, maxCount := 0, 0
+	for _, pointVal := range pointValues {
+		labelWidth = max(labelWidth, len(pointVal))
+		maxCount = max(maxCount, distribution[pointVal])
+	}
+
+	var s strings.Builder
+	for _, pointVal := range pointValues {
+		count := distribution[pointVal]
+		bar := 0
+		if maxCount > 0 {
+			bar = max(count*histogramWidth/maxCount, 1)
+		}
+		fmt.Fprintf(&s, "%-*s |%s %d\n", labelWidth, pointVal, strings.Repeat("█", bar), count)
+	}
+	return s.String()
+}
+
+// percentLabel formats the share of a point value in the distribution, like
+// "(40.0%)", and "(40.0% of 5 players)" when it's computed over all players.
+func percentLabel(percentage float64, total int) string {
+	if !options.PercentOfPlayers {
+		return fmt.Sprintf("(%.1f%%)", percentage*100)
+	}
+	if total == 1 {
+		return t("stats.percentOf.one", percentage*100, total)
+	}
+	return t("stats.percentOf.other", percentage*100, total)
+}
+
+// renderCompact renders the distribution as one line per point value like
+// "5: 3 (60%)", without bars, with percentages over total.
+func renderCompact(distribution map[string]int, total int) string {
+	var s strings.Builder
+	for _, pointVal := range sortedPointValues(distribution) {
+		count := distribution[pointVal]
+		fmt.Fprintf(&s, "%s: %d (%.0f%%)\n", pointVal, count, float64(count)/float64(total)*100)
+	}
+	return s.String()
+}
+
 // showFinalVotes renders a formatted string displaying voting statistics including
-// average, median, and a visual distribution with progress bars for each point value.
-// It takes the list of voted points and total vote count as parameters.
-func showFinalVotes(points []string, voted int) string {
+// average, median, and a visual distribution with progress bars, an ASCII
+// histogram, or a compact line for each point value.
+// It takes the list of voted points, the total vote count, and the number of
+// connected players, over which percentages are computed with -percent-of-players.
+func showFinalVotes(points []string, voted, players int) string {
 	var s strings.Builder
 
 	avg, median, distribution := calculateStatistics(points)
 
 	p := progress.New(
-		progress.WithScaledGradient(catppuccinMaroon, catppuccinLavender),
+		withScaledGradient(theme.GradientStart, theme.GradientEnd),
 		progress.WithWidth(50),
 	)
 
-	s.WriteString("\n📊 Voting Statistics:\n")
+	s.WriteString("\n" + t("stats.title") + "\n")
 	if avg > 0 {
-		fmt.Fprintf(&s, "Average: %.1f\n", avg)
+		s.WriteString(t("stats.average", formatStat(avg)) + "\n")
+		if values := numericPoints(points); options.TrimmedMean && len(values) >= 3 {
+			s.WriteString(t("stats.trimmed", formatStat(trimmedMean(values))) + "\n")
+		}
+		if options.Suggest {
+			if card := nearestCard(avg, activeDeck()); card != "" {
+				s.WriteString(t("stats.suggested", card) + "\n")
+			}
+		}
+	}
+	s.WriteString(t("stats.median", median) + "\n")
+	if wideSpread(numericPoints(points), options.Spread) {
+		s.WriteString(spreadStyle.Render(t("stats.wideSpread")) + "\n")
 	}
-	fmt.Fprintf(&s, "Median: %s\n", median)
 
-	s.WriteString("Distribution:\n")
-	// Sort point values for consistent display
-	pointValues := make([]string, 0, len(distribution))
-	for p := range distribution {
-		pointValues = append(pointValues, p)
+	total := voted
+	if options.PercentOfPlayers && players > 0 {
+		total = players
 	}
-	sort.Strings(pointValues)
 
-	for _, pointVal := range pointValues {
+	s.WriteString(t("stats.distribution") + "\n")
+	if options.Compact {
+		s.WriteString(renderCompact(distribution, total))
+		return s.String()
+	}
+	if options.Chart == chartASCII {
+		s.WriteString(renderHistogram(distribution))
+		return s.String()
+	}
+
+	for _, pointVal := range sortedPointValues(distribution) {
 		count := distribution[pointVal]
-		percentage := float64(count) / float64(voted)
+		percentage := float64(count) / float64(total)
 
 		label := labelStyle.Render(pointVal + ":")
-		votes := countStyle.Render(fmt.Sprintf("%d votes", count))
-		percent := percentStyle.Render(fmt.Sprintf("(%.1f%%)", percentage*100))
+		votes := countStyle.Render(plural("stats.votes", count))
+		percent := percentStyle.Render(percentLabel(percentage, total))
 
 		// Add the point value and vote count
 		fmt.Fprintf(&s, "%s %s %s\n", label, votes, percent)
@@ -254,21 +639,187 @@ func showFinalVotes(points []string, voted int) string {
 	return s.String()
 }
 
-// gameState holds the shared state for a Scrum Poker session, including all
-// connected players, reveal status, and the master connection reference.
+// gameState holds the shared state for a Scrum Poker session: the players and
+// the keys of everyone who joined, the reveal, the round and its history, the
+// running timer, the backlog, the chat and activity feed, and the connected
+// Scrum Masters with their names and the facilitators.
+//
+// A reveal closes voting and shows the votes to the master. Players see them
+// too unless -blind-reveal is set, in which case the master shares them with a
+// second key press. Either way the players only see them once the suspense
+// countdown of the reveal has ended at revealAt. While sealed votes are left to
+// open, the reveal event and the estimate write-back are pending.
+//
+// The deck is nil until the Scrum Master switches decks, and deckVersion
+// counts the switches so player views know to rebuild their card list.
+//
+// While frozen, new players can't join; players who lost their connection may
+// still reconnect.
+//
+// timerID, autoClearID and revealID count the timers, auto-clears and reveals
+// started by any master, so each master ignores the messages of those that
+// another one has since replaced or cancelled.
+//
+// Votes only hold mu for reading, so votingStart, set by the first vote, is
+// additionally guarded by votingStartMu, and the activity feed by activityMu.
 type gameState struct {
-	players    map[string]*playerState
-	revealed   bool
-	mu         sync.RWMutex
-	masterConn ssh.Session
+	players         map[string]*playerState
+	playerOrder     []string
+	masterRevealed  bool
+	playersRevealed bool
+	revealAt        time.Time
+	revealPending   bool
+	estimatePending bool
+	round           int
+	attempt         int
+	roundsPlayed    int
+	lastPlayedRound int
+	roundStart      time.Time
+	votingStart     time.Time
+	votingStartMu   sync.Mutex
+	history         []roundRecord
+	announcement    string
+	chat            []chatMessage
+	backlog         []Story
+	storyIndex      int
+	activity        []activityEvent
+	activityMu      sync.Mutex
+	timerKind       timerKind
+	timerEnd        time.Time
+	timerID         int
+	autoClearID     int
+	revealID        int
+	frozen          bool
+	deck            []string
+	deckVersion     int
+	mu              sync.RWMutex
+	masters         map[ssh.Session]string
+	facilitators    map[ssh.Session]bool
+	joined          map[string]bool
+}
+
+// sortedPlayerKeys returns the keys of the players map sorted by name, for a
+// consistent display order. The slice is kept up to date on join and leave, so
+// rendering doesn't sort or allocate; it must not be modified or retained
+// after releasing the lock. Callers must hold state.mu.
+func sortedPlayerKeys() []string {
+	return state.playerOrder
+}
+
+// putPlayer adds or replaces the player under key, keeping the sorted player
+// order in sync. Callers must hold g.mu for writing.
+func (g *gameState) putPlayer(key string, player *playerState) {
+	if _, exists := g.players[key]; !exists {
+		i := sort.SearchStrings(g.playerOrder, key)
+		g.playerOrder = slices.Insert(g.playerOrder, i, key)
+	}
+	g.players[key] = player
+}
+
+// deletePlayer removes the player under key and from the sorted player order.
+// Callers must hold g.mu for writing.
+func (g *gameState) deletePlayer(key string) {
+	if _, exists := g.players[key]; !exists {
+		return
+	}
+	i := sort.SearchStrings(g.playerOrder, key)
+	g.playerOrder = slices.Delete(g.playerOrder, i, i+1)
+	delete(g.players, key)
 }
 
-// playerState holds the state for an individual player including their selected
-// points, SSH session reference, and whether they have made a selection.
+// resetPlayers removes all players. Callers must hold g.mu for writing.
+func (g *gameState) resetPlayers() {
+	g.players = make(map[string]*playerState)
+	g.playerOrder = nil
+}
+
+// newGameState returns an empty game state at the first attempt of the first round.
+func newGameState() *gameState {
+	return &gameState{
+		players:      make(map[string]*playerState),
+		masters:      make(map[ssh.Session]string),
+		facilitators: make(map[ssh.Session]bool),
+		joined:       make(map[string]bool),
+		round:        1,
+		attempt:      1,
+		roundStart:   time.Now(),
+	}
+}
+
+// playerState holds the state for an individual player including their display
+// name and color, selected points and risk, sealed commitment, current
+// reaction, when they first voted in the round and last changed their vote, how
+// often they changed it, whisper channel, SSH session reference and the
+// fingerprint of the key they joined with, whether they have made a selection,
+// how confident they are about it, had it revealed early, abstained by letting
+// the voting timer run out, or checked in as ready, and whether they are
+// offline, having lost their connection or been restored from a state file.
+//
+// The vote fields (points, selected, risk, riskSelected, confidence, reaction,
+// votedAt, changedAt, voteChanges, commit, ready, revealed and abstained) are
+// guarded by mu so a player can vote while others hold state.mu for reading,
+// such as the master rendering its view. They're changed with state.mu held for
+// reading and mu held, or with state.mu held for writing, and read through vote
+// unless state.mu is held for writing.
 type playerState struct {
-	points   string
-	session  ssh.Session
-	selected bool
+	name         string
+	color        lipgloss.Color
+	points       string
+	risk         string
+	reaction     string
+	votedAt      time.Time
+	whispers     chan string
+	session      ssh.Session
+	selected     bool
+	riskSelected bool
+	confidence   confidence
+	revealed     bool
+	abstained    bool
+	ready        bool
+	offline      bool
+	fingerprint  string
+	commit       string
+	changedAt    time.Time
+	voteChanges  int
+	mu           sync.Mutex
+}
+
+// playerVote is a consistent copy of the vote fields of a player.
+type playerVote struct {
+	points       string
+	risk         string
+	reaction     string
+	votedAt      time.Time
+	selected     bool
+	riskSelected bool
+	confidence   confidence
+	revealed     bool
+	abstained    bool
+	ready        bool
+	committed    bool
+	changedAt    time.Time
+	voteChanges  int
+}
+
+// vote returns a copy of the player's vote fields. Callers must hold state.mu.
+func (p *playerState) vote() playerVote {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return playerVote{
+		points:       p.points,
+		risk:         p.risk,
+		riskSelected: p.riskSelected,
+		reaction:     p.reaction,
+		votedAt:      p.votedAt,
+		selected:     p.selected,
+		revealed:     p.revealed,
+		abstained:    p.abstained,
+		ready:        p.ready,
+		confidence:   p.confidence,
+		committed:    p.commit != "",
+		changedAt:    p.changedAt,
+		voteChanges:  p.voteChanges,
+	}
 }
 
 // some variables for both master and player
@@ -278,22 +829,42 @@ var (
 	helpStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color(catppuccinOverlay1)).Render
 )
 
+// validateAuthorizedKeysPath checks that the authorized keys file of the Scrum
+// Masters can be read. A missing file is fine, as the Scrum Master may sign in
+// with the master password instead.
+func validateAuthorizedKeysPath(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to access authorized keys %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("authorized keys %s is a directory, expected a file with one key per line", path)
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrPermission) {
+		return fmt.Errorf("authorized keys %s is not readable, check its permissions: %w", path, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open authorized keys %s: %w", path, err)
+	}
+	return f.Close()
+}
+
 // checkAuthorizedKey validates whether the SSH session's public key matches
-// any key in the .ssh/showdown_keys file. Returns true if the key is authorized,
-// which grants Scrum Master privileges to the connecting user.
-func checkAuthorizedKey(s ssh.Session) bool {
+// any key in the authorized keys file, .ssh/showdown_keys unless set with
+// -keys. Returns true if the key is authorized, which grants Scrum Master
+// privileges to the connecting user.
+func checkAuthorizedKey(s ssh.Session, authorizedKeysPath string) bool {
 	pubKey := s.PublicKey()
 	if pubKey == nil {
 		log.Warn("No public key found!")
 		return false
 	}
 
-	authorizedKeysPath, err := getConfigPath("showdown_keys")
-	if err != nil {
-		log.Error("failed to resolve authorized_keys path", "error", err)
-		return false
-	}
-
 	authorizedKeys, err := os.ReadFile(authorizedKeysPath)
 	if err != nil {
 		log.Error("failed to read authorized_keys", "error", err, "path", authorizedKeysPath)
@@ -318,30 +889,197 @@ func checkAuthorizedKey(s ssh.Session) bool {
 	return false
 }
 
-// pokerHandler is the main Bubble Tea handler for SSH connections. It determines
-// whether to show the Scrum Master view (for authorized keys when no master exists)
-// or the player name input view for regular participants.
-func pokerHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+// contextKey is the type of the values stored on the SSH context by Showdown.
+type contextKey string
+
+// masterEligibleKey marks a session whose user answered the master password
+// correctly during keyboard-interactive auth.
+const masterEligibleKey contextKey = "master-eligible"
+
+// matchMasterPassword reports whether the answers to the keyboard-interactive
+// challenge consist of a single answer equal to the master password.
+func matchMasterPassword(answers []string, password string) bool {
+	if password == "" || len(answers) != 1 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(answers[0]), []byte(password)) == 1
+}
+
+// keyboardInteractiveAuth returns the keyboard-interactive handler. Without a
+// master password it succeeds immediately so players can join without a key.
+// With a master password it asks for it once and marks the session as master
+// eligible on a match; wrong or empty answers still join as a player.
+func keyboardInteractiveAuth(password string) func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool {
+	return func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
+		if bans.bannedAddr(ctx.RemoteAddr()) {
+			log.Warn("Refused banned address", "remote", ctx.RemoteAddr())
+			return false
+		}
+		if password == "" {
+			return true
+		}
+
+		answers, err := challenger(ctx.User(), "", []string{"Master password (leave empty to join as player): "}, []bool{false})
+		if err != nil {
+			return false
+		}
+		if matchMasterPassword(answers, password) {
+			ctx.SetValue(masterEligibleKey, true)
+		}
+		return true
+	}
+}
+
+// publicKeyAuth accepts any ed25519 key that isn't banned, from an address
+// that isn't banned.
+func publicKeyAuth(ctx ssh.Context, key ssh.PublicKey) bool {
+	if bans.bannedKey(key) {
+		log.Warn("Refused banned key", "fingerprint", gossh.FingerprintSHA256(key), "remote", ctx.RemoteAddr())
+		return false
+	}
+	if bans.bannedAddr(ctx.RemoteAddr()) {
+		log.Warn("Refused banned address", "remote", ctx.RemoteAddr())
+		return false
+	}
+	// Allow connections with any ed25519 key
+	return key != nil && key.Type() == "ssh-ed25519"
+}
+
+// authOptions returns the authentication options of the SSH server. Unless a
+// key is required, keyless players join through keyboard-interactive auth.
+func authOptions(masterPassword string, requireKey bool) []ssh.Option {
+	opts := []ssh.Option{wish.WithPublicKeyAuth(publicKeyAuth)}
+	if !requireKey {
+		// Add keyboard-interactive auth that only prompts for the optional master password
+		// HACK(robin): need to allow normal players to join. For those who don't have a public key set
+		opts = append(opts, wish.WithKeyboardInteractiveAuth(keyboardInteractiveAuth(masterPassword)))
+	}
+	return opts
+}
+
+// isMasterEligible reports whether the session authenticated with the master
+// password.
+func isMasterEligible(s ssh.Session) bool {
+	eligible, _ := s.Context().Value(masterEligibleKey).(bool)
+	return eligible
+}
+
+// fallbackHost is listened on when the hostname can't be determined.
+const fallbackHost = "0.0.0.0"
+
+// listenHost returns the host the server listens on: the -addr flag when set,
+// otherwise the machine's hostname. When the hostname can't be determined, or
+// is empty, it returns fallbackHost together with the reason, rather than
+// silently listening on an empty host.
+func listenHost(addr string, hostname func() (string, error)) (string, error) {
+	if addr != "" {
+		return addr, nil
+	}
+	host, err := hostname()
+	if err != nil {
+		return fallbackHost, err
+	}
+	if host == "" {
+		return fallbackHost, errors.New("empty hostname")
+	}
+	return host, nil
+}
+
+// claimMaster adds the session to the Scrum Masters under the given name, who
+// share control of the game. It returns the previous session of the same key
+// when it reconnects before its dropped connection was noticed, which the
+// caller closes. Callers must hold state.mu.
+func claimMaster(s ssh.Session, name string) ssh.Session {
+	var stale ssh.Session
+	if fingerprint := keyFingerprint(s.PublicKey()); fingerprint != "" {
+		for other := range state.masters {
+			if keyFingerprint(other.PublicKey()) == fingerprint {
+				stale = other
+				delete(state.masters, other)
+			}
+		}
+	}
+	state.masters[s] = name
+	return stale
+}
+
+// masterList returns the names of the connected Scrum Masters, sorted and
+// joined for display. Callers must hold state.mu.
+func masterList() string {
+	names := slices.Sorted(maps.Values(state.masters))
+	return strings.Join(names, ", ")
+}
+
+// keyFingerprint returns the SHA256 fingerprint of key, or an empty string
+// without a key.
+func keyFingerprint(key ssh.PublicKey) string {
+	if key == nil {
+		return ""
+	}
+	return gossh.FingerprintSHA256(key)
+}
+
+// pokerHandler returns the main Bubble Tea handler for SSH connections. It
+// determines whether to show the Scrum Master view (for keys authorized in the
+// configured keys file or the master password, shared by all masters)
+// or the player name input view for regular participants. One-shot commands
+// cast or seal a vote and exit, and clients without a terminal vote in line mode.
+func pokerHandler(cfg Config) bubbletea.Handler {
+	return func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+		return handleSession(s, cfg.KeysPath)
+	}
+}
+
+// handleSession picks the view of a new SSH session for pokerHandler.
+func handleSession(s ssh.Session, authorizedKeysPath string) (tea.Model, []tea.ProgramOption) {
+	if len(s.Command()) > 0 {
+		switch s.Command()[0] {
+		case "commit":
+			commandCommit(s)
+		case "open":
+			commandOpen(s)
+		default:
+			commandVote(s)
+		}
+		return nil, nil
+	}
+
 	_, _, active := s.Pty()
 	if !active {
-		wish.Fatalln(s, "no active terminal, skipping")
+		lineModeVote(s)
 		return nil, nil
 	}
 
-	// Check if the connection has valid authorized key
-	if checkAuthorizedKey(s) {
+	// Check if the connection has valid authorized key or the master password
+	if checkAuthorizedKey(s, authorizedKeysPath) || isMasterEligible(s) {
 		// Set Scrum Master connection view when there is none (thread-safe).
+		name := masterDisplayName(s.PublicKey(), s.User())
 		state.mu.Lock()
-		if state.masterConn == nil {
-			state.masterConn = s
-			state.mu.Unlock()
-			log.Info("Scrum Master connected", "user", s.User())
-			return newMasterView(), []tea.ProgramOption{tea.WithAltScreen()}
-		}
+		stale := claimMaster(s, name)
 		state.mu.Unlock()
+		if stale != nil {
+			log.Info("Scrum Master reconnected, closing the previous session", "name", name)
+			go disconnectSession(name, stale)
+		}
+		log.Info("Scrum Master connected", "name", name, "user", s.User())
+		m := newMasterView()
+		m.out = s
+		return m, []tea.ProgramOption{tea.WithAltScreen()}
+	}
+
+	// Facilitators help run the session next to the Scrum Master
+	if isFacilitator(s) {
+		addFacilitator(s)
+		log.Info("Facilitator connected", "user", s.User())
+		m := newFacilitatorView()
+		m.out = s
+		return m, []tea.ProgramOption{tea.WithAltScreen()}
+	}
 
-		// If master already exists, deny connection
-		wish.Fatalln(s, "Scrum Master is already connected")
+	// Refuse latecomers up front while joins are locked, unless a player who
+	// lost their connection may be coming back
+	if joinsLocked() {
+		wish.Fatalln(s, errJoinsLocked.Error())
 		return nil, nil
 	}
 
@@ -350,34 +1088,47 @@ func pokerHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
 	return initialNameInputView(s), []tea.ProgramOption{tea.WithAltScreen()}
 }
 
-// connectionLimitMiddleware enforces global and per-IP connection limits to prevent DoS attacks.
-func connectionLimitMiddleware() wish.Middleware {
-	return func(h ssh.Handler) ssh.Handler {
-		return func(s ssh.Session) {
-			// Global limit check
-			if connectionCount.Load() >= maxConnections {
-				wish.Fatalln(s, fmt.Sprintf("Server at capacity (%d connections), please try again later", maxConnections))
-				return
-			}
-			connectionCount.Add(1)
-			defer connectionCount.Add(-1)
-
-			// Per-IP limit check
-			clientIP := s.RemoteAddr().String()
-			// Extract just the IP without port
-			if host, _, err := net.SplitHostPort(clientIP); err == nil {
-				clientIP = host
-			}
+// acquireConnection counts a connection from addr against the global and
+// per-IP connection limits, returning the function releasing it, or an error
+// when a limit is reached.
+func acquireConnection(addr net.Addr, maxConnections, maxConnectionsPerIP int) (func(), error) {
+	// Global limit check
+	if int(connectionCount.Load()) >= maxConnections {
+		return nil, fmt.Errorf("server at capacity (%d connections), please try again later", maxConnections)
+	}
 
-			ipCountI, _ := connectionsByIP.LoadOrStore(clientIP, &atomic.Int32{})
-			ipCount := ipCountI.(*atomic.Int32)
+	// Per-IP limit check
+	clientIP := addr.String()
+	// Extract just the IP without port
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		clientIP = host
+	}
+
+	ipCountI, _ := connectionsByIP.LoadOrStore(clientIP, &atomic.Int32{})
+	ipCount := ipCountI.(*atomic.Int32)
 
-			if ipCount.Load() >= maxConnectionsPerIP {
-				wish.Fatalln(s, "Too many connections from your IP address")
+	if int(ipCount.Load()) >= maxConnectionsPerIP {
+		return nil, errors.New("too many connections from your IP address")
+	}
+	connectionCount.Add(1)
+	ipCount.Add(1)
+	return func() {
+		ipCount.Add(-1)
+		connectionCount.Add(-1)
+	}, nil
+}
+
+// connectionLimitMiddleware enforces global and per-IP connection limits to
+// prevent DoS attacks.
+func connectionLimitMiddleware(maxConnections, maxConnectionsPerIP int) wish.Middleware {
+	return func(h ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			release, err := acquireConnection(s.RemoteAddr(), maxConnections, maxConnectionsPerIP)
+			if err != nil {
+				wish.Fatalln(s, err)
 				return
 			}
-			ipCount.Add(1)
-			defer ipCount.Add(-1)
+			defer release()
 
 			h(s)
 		}
@@ -410,33 +1161,48 @@ func sessionTimeoutMiddleware() wish.Middleware {
 }
 
 // sessionCloseMiddleware returns a Wish middleware that handles SSH session cleanup.
-// It resets the terminal state when sessions close and clears the master connection
-// reference if the disconnecting session was the Scrum Master.
+// It resets the terminal state when sessions with a terminal close, removes the
+// disconnecting session from the Scrum Masters and facilitators, and marks a
+// player who lost their connection as offline so they can reconnect.
 func sessionCloseMiddleware() wish.Middleware {
 	return func(h ssh.Handler) ssh.Handler {
 		return func(s ssh.Session) {
 			// Run the handler (this blocks until session ends)
 			h(s)
 
-			// Reset terminal state before session closes
-			resetTerminal(s)
+			// Reset terminal state before session closes, leaving the output
+			// of one-shot commands and line mode without a terminal alone
+			if _, _, active := s.Pty(); active {
+				resetTerminal(s)
+			}
 
 			// After session ends, check if it was the master connection
 			state.mu.Lock()
 			defer state.mu.Unlock()
-			if state.masterConn == s {
-				state.masterConn = nil
-				log.Info("Scrum Master disconnected, reset connection")
+			if name, ok := state.masters[s]; ok {
+				delete(state.masters, s)
+				log.Info("Scrum Master disconnected", "name", name)
 			}
+			removeFacilitator(s)
+			markOffline(s)
 		}
 	}
 }
 
-// main is the application entry point. It initializes version information from
-// build flags or runtime, parses command-line flags for port configuration,
-// creates and starts the SSH server with authentication and middleware, and
-// handles graceful shutdown on interrupt signals.
+// main is the application entry point. It runs the init subcommand when given,
+// otherwise it initializes version information from build flags or runtime,
+// parses the configuration, and runs the server until interrupted. It exits
+// with exitConfig on an invalid configuration and exitStartup when the server
+// fails.
 func main() {
+	// The init subcommand writes sample config files instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		if err := runInit(os.Args[2:], os.Stdout); err != nil && !errors.Is(err, flag.ErrHelp) {
+			fatal(exitStartup, "failed to write sample config", "error", err)
+		}
+		return
+	}
+
 	if Version == "" {
 		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Sum != "" {
 			Version = info.Main.Version
@@ -448,81 +1214,38 @@ func main() {
 	if len(CommitSHA) >= shaLen {
 		version += " (" + CommitSHA[:shaLen] + ")"
 	}
+	serverVersion = version
+	serverStart = time.Now()
 
-	// define flag for custom port
-	port := flag.Int("p", 23234, "SSH server port")
-	// Parse all declared flags
-	flag.Parse()
-
-	host, err := os.Hostname()
-	if err != nil {
-		log.Error("couldn't determine hostname: %v", err)
+	cfg, err := parseConfig(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
-
-	// Convert port into string
-	portStr := strconv.Itoa(*port)
-
-	// Get absolute path for host key
-	hostKeyPath, err := getConfigPath("showdown_ed25519")
-	if err != nil {
-		log.Fatal("failed to resolve host key path", "error", err)
-	}
-
-	// create SSH server
-	s, err := wish.NewServer(
-		wish.WithAddress(net.JoinHostPort(host, portStr)),
-		wish.WithHostKeyPath(hostKeyPath),
-		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
-			// Allow connections with any ed25519 key
-			return key != nil && key.Type() == "ssh-ed25519"
-		}),
-		// Add keyboard-interactive auth that immediately succeeds without prompting
-		// HACK(robin): need to allow normal players to join. For those who don't have a public key set
-		wish.WithKeyboardInteractiveAuth(func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
-			// Authenticate immediately without any challenge
-			return true
-		}),
-		wish.WithMiddleware(
-			connectionLimitMiddleware(),
-			sessionTimeoutMiddleware(),
-			bubbletea.Middleware(pokerHandler),
-			logging.Middleware(),
-			sessionCloseMiddleware(),
-		),
-	)
 	if err != nil {
-		log.Error("Could not start server", "error", err)
+		fatal(exitConfig, "invalid configuration", "error", err)
 	}
+	applySettings(cfg)
 
-	// Open SSH listerner and serve SSH. Make it possible to stop the service
-	done := make(chan os.Signal, 1)
-	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-	log.Info("Starting Showdown server", "host", host, "port", *port, "version", version)
-	go func() {
-		if err = s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
-			log.Error("Could not start server", "error", err)
-			done <- nil
+	// Print the host key fingerprints and exit, for pinning in known_hosts
+	if cfg.PrintFingerprint {
+		if err := printFingerprints(cfg.HostKeys, os.Stdout); err != nil {
+			fatal(exitStartup, "failed to print host key fingerprints", "error", err)
 		}
-	}()
-
-	<-done
-	log.Info("Stopping Showdown server")
-
-	// Reset terminal for all active sessions before shutdown
-	state.mu.RLock()
-	if state.masterConn != nil {
-		resetTerminal(state.masterConn)
+		return
 	}
-	for _, player := range state.players {
-		if player.session != nil {
-			resetTerminal(player.session)
+
+	// Validate the configuration and exit, for health checks before rollout
+	if cfg.Check {
+		if !runChecks(configChecks(cfg), os.Stdout) {
+			os.Exit(exitConfig)
 		}
+		return
 	}
-	state.mu.RUnlock()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer func() { cancel() }()
-	if err := s.Shutdown(ctx); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
-		log.Error("Could not stop server", "error", err)
+	if err := loadConfigFiles(cfg); err != nil {
+		fatal(exitConfig, "invalid configuration", "error", err)
+	}
+	if err := run(cfg); err != nil {
+		fatal(exitStartup, "Could not run server", "error", err)
 	}
 }
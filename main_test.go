@@ -1,8 +1,22 @@
 package main
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 // TestCalculateStatistics tests the statistics calculation function with various inputs
@@ -99,6 +113,105 @@ func TestCalculateStatistics(t *testing.T) {
 	}
 }
 
+// TestStatisticsPrecision tests the average and median formatting for each precision
+func TestStatisticsPrecision(t *testing.T) {
+	defer func(precision int) { options.Precision = precision }(options.Precision)
+
+	points := []string{"0.5", "1", "2", "3"}
+	tests := []struct {
+		precision   int
+		wantMedian  string
+		wantAverage string
+	}{
+		{0, "2", "Average: 2\n"},
+		{1, "1.5", "Average: 1.6\n"},
+		{2, "1.50", "Average: 1.62\n"},
+		{3, "1.500", "Average: 1.625\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("precision_%d", tt.precision), func(t *testing.T) {
+			options.Precision = tt.precision
+
+			if _, median, _ := calculateStatistics(points); median != tt.wantMedian {
+				t.Errorf("calculateStatistics() median = %v, want %v", median, tt.wantMedian)
+			}
+			if got := showFinalVotes(points, len(points), len(points)); !strings.Contains(got, tt.wantAverage) {
+				t.Errorf("showFinalVotes() missing %q\nGot: %s", tt.wantAverage, got)
+			}
+		})
+	}
+}
+
+// TestMedianModes tests the interpolated and lower-middle median of even vote counts
+func TestMedianModes(t *testing.T) {
+	defer func(lower bool) { options.MedianLower = lower }(options.MedianLower)
+
+	tests := []struct {
+		name   string
+		points []string
+		lower  bool
+		want   string
+	}{
+		{"interpolated even count", []string{"3", "5"}, false, "4.0"},
+		{"lower even count", []string{"3", "5"}, true, "3.0"},
+		{"lower with non-numeric", []string{"8", "?", "2", "5", "13"}, true, "5.0"},
+		{"lower odd count unchanged", []string{"1", "3", "5"}, true, "3.0"},
+		{"lower without numeric", []string{"?", "☕"}, true, "N/A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options.MedianLower = tt.lower
+			if _, median, _ := calculateStatistics(tt.points); median != tt.want {
+				t.Errorf("calculateStatistics() median = %v, want %v", median, tt.want)
+			}
+		})
+	}
+}
+
+// TestTrimmedMean tests dropping the highest and lowest value before averaging
+func TestTrimmedMean(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"no values", nil, 0},
+		{"two values not trimmed", []float64{3, 13}, 8},
+		{"three values keep the middle", []float64{13, 1, 5}, 5},
+		{"four values keep the middle two", []float64{2, 40, 3, 5}, 4},
+		{"duplicate extremes drop one each", []float64{5, 5, 8, 8}, 6.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trimmedMean(tt.values); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("trimmedMean(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestShowFinalVotesTrimmedMean tests that the trimmed mean is only shown
+// when enabled and there are at least three numeric votes
+func TestShowFinalVotesTrimmedMean(t *testing.T) {
+	defer func(show bool) { options.TrimmedMean = show }(options.TrimmedMean)
+
+	options.TrimmedMean = true
+	if got := showFinalVotes([]string{"1", "5", "5", "40", "?"}, 5, 5); !strings.Contains(got, "Trimmed avg: 5.0\n") {
+		t.Errorf("showFinalVotes() missing trimmed mean\nGot: %s", got)
+	}
+	if got := showFinalVotes([]string{"3", "5"}, 2, 2); strings.Contains(got, "Trimmed avg") {
+		t.Errorf("showFinalVotes() shows trimmed mean for two votes\nGot: %s", got)
+	}
+
+	options.TrimmedMean = false
+	if got := showFinalVotes([]string{"1", "5", "5", "40"}, 4, 4); strings.Contains(got, "Trimmed avg") {
+		t.Errorf("showFinalVotes() shows trimmed mean when disabled\nGot: %s", got)
+	}
+}
+
 // TestShowFinalVotes tests the final votes display function
 func TestShowFinalVotes(t *testing.T) {
 	tests := []struct {
@@ -117,7 +230,7 @@ func TestShowFinalVotes(t *testing.T) {
 				"Median: 5.0",
 				"Distribution:",
 				"5:",
-				"1 votes",
+				"1 vote",
 				"100.0%",
 			},
 		},
@@ -151,7 +264,7 @@ func TestShowFinalVotes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := showFinalVotes(tt.points, tt.voted)
+			got := showFinalVotes(tt.points, tt.voted, tt.voted)
 
 			for _, substr := range tt.wantSubstr {
 				if !strings.Contains(got, substr) {
@@ -168,9 +281,9 @@ func TestTimerDurations(t *testing.T) {
 		key      string
 		wantSecs int
 	}{
-		{"1", 15},
-		{"3", 30},
-		{"6", 60},
+		{"f1", 15},
+		{"f3", 30},
+		{"f6", 60},
 	}
 
 	for _, tt := range tests {
@@ -204,3 +317,493 @@ func TestPointOptions(t *testing.T) {
 		}
 	}
 }
+
+// TestValidatePlayerNameReserved tests that reserved names are refused
+func TestValidatePlayerNameReserved(t *testing.T) {
+	tests := []struct {
+		name       string
+		playerName string
+		wantErr    bool
+	}{
+		{"reserved master", "master", true},
+		{"reserved with casing", "Admin", true},
+		{"reserved with extra spaces", "Scrum   Master", true},
+		{"only separators", "__--", true},
+		{"normal name", "alice", false},
+		{"name containing reserved word", "master chief", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePlayerName(tt.playerName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePlayerName(%q) error = %v, wantErr %v", tt.playerName, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestParseReservedNames tests parsing of the -reserved flag value
+func TestParseReservedNames(t *testing.T) {
+	got := parseReservedNames(" Master, scrum  master,,Bot ")
+	want := []string{"master", "scrum master", "bot"}
+
+	if len(got) != len(want) {
+		t.Fatalf("parseReservedNames() returned %d names, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseReservedNames()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// TestPlayerColorDeterministic tests that a name always maps to the same color
+func TestPlayerColorDeterministic(t *testing.T) {
+	names := []string{"alice", "bob", "charlie", "Dana"}
+
+	for _, name := range names {
+		first := playerColor(name)
+		for i := 0; i < 5; i++ {
+			if got := playerColor(name); got != first {
+				t.Errorf("playerColor(%q) = %s, want %s", name, got, first)
+			}
+		}
+	}
+
+	if playerColor("Alice") != playerColor("alice") {
+		t.Errorf("playerColor() differs by casing, want same color")
+	}
+}
+
+// TestMatchMasterPassword tests the keyboard-interactive master password matcher
+func TestMatchMasterPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		answers  []string
+		password string
+		want     bool
+	}{
+		{"correct password", []string{"s3cret"}, "s3cret", true},
+		{"wrong password", []string{"guess"}, "s3cret", false},
+		{"empty answer", []string{""}, "s3cret", false},
+		{"no answers", nil, "s3cret", false},
+		{"too many answers", []string{"s3cret", "s3cret"}, "s3cret", false},
+		{"password disabled", []string{""}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchMasterPassword(tt.answers, tt.password); got != tt.want {
+				t.Errorf("matchMasterPassword(%v) = %v, want %v", tt.answers, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNearestCard tests snapping the average to the closest card of the deck
+func TestNearestCard(t *testing.T) {
+	tests := []struct {
+		name string
+		avg  float64
+		deck []string
+		want string
+	}{
+		{"exact card", 5, pointOptions, "5"},
+		{"closer to lower card", 3.8, pointOptions, "3"},
+		{"closer to upper card", 4.2, pointOptions, "5"},
+		{"mid-point rounds up", 4, pointOptions, "5"},
+		{"decimal mid-point rounds up", 0.75, pointOptions, "1"},
+		{"decimal deck", 0.3, []string{"0.1", "0.25", "0.5", "?"}, "0.25"},
+		{"above largest card", 42, pointOptions, "10"},
+		{"no numeric cards", 3, []string{"?", "☕"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nearestCard(tt.avg, tt.deck); got != tt.want {
+				t.Errorf("nearestCard(%v) = %q, want %q", tt.avg, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRenderHistogram tests that the ASCII bar lengths scale with the vote counts
+func TestRenderHistogram(t *testing.T) {
+	distribution := map[string]int{"8": 4, "3": 1, "?": 1, "10": 2}
+
+	lines := strings.Split(strings.TrimSuffix(renderHistogram(distribution), "\n"), "\n")
+
+	tests := []struct {
+		label   string
+		wantBar int
+	}{
+		{"3 ", histogramWidth / 4},
+		{"8 ", histogramWidth},
+		{"10", histogramWidth / 2},
+		{"? ", histogramWidth / 4},
+	}
+
+	if len(lines) != len(tests) {
+		t.Fatalf("renderHistogram() returned %d lines, want %d", len(lines), len(tests))
+	}
+	for i, tt := range tests {
+		if !strings.HasPrefix(lines[i], tt.label+" |") {
+			t.Errorf("line %d = %q, want label %q", i, lines[i], tt.label)
+		}
+		if got := strings.Count(lines[i], "█"); got != tt.wantBar {
+			t.Errorf("line %d bar length = %d, want %d", i, got, tt.wantBar)
+		}
+	}
+}
+
+// TestWideSpread tests flagging rounds for discussion at the threshold
+// boundary
+func TestWideSpread(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []float64
+		threshold float64
+		want      bool
+	}{
+		{"exactly twice", []float64{2, 3, 4}, 2, false},
+		{"just over twice", []float64{2, 4.5}, 2, true},
+		{"within threshold", []float64{3, 5}, 2, false},
+		{"half point votes", []float64{0.5, 1}, 2, false},
+		{"zero vote", []float64{0, 1}, 2, true},
+		{"higher threshold", []float64{2, 5}, 3, false},
+		{"single vote", []float64{8}, 2, false},
+		{"no votes", nil, 2, false},
+		{"disabled", []float64{1, 10}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wideSpread(tt.values, tt.threshold); got != tt.want {
+				t.Errorf("wideSpread(%v, %v) = %v, want %v", tt.values, tt.threshold, got, tt.want)
+			}
+		})
+	}
+
+	if got := showFinalVotes([]string{"1", "3", "?"}, 3, 3); !strings.Contains(got, "⚠ Wide spread — discuss before accepting") {
+		t.Errorf("showFinalVotes() of 1 and 3 doesn't flag the spread:\n%s", got)
+	}
+	if got := showFinalVotes([]string{"2", "3"}, 2, 2); strings.Contains(got, "Wide spread") {
+		t.Errorf("showFinalVotes() of 2 and 3 flags the spread:\n%s", got)
+	}
+}
+
+// TestCompactResults tests that the compact distribution has one line per card
+// and no bars
+func TestCompactResults(t *testing.T) {
+	options.Compact = true
+	defer func() { options.Compact = false }()
+
+	got := showFinalVotes([]string{"5", "8", "5", "3", "5"}, 5, 5)
+
+	for _, want := range []string{"3: 1 (20%)\n", "5: 3 (60%)\n", "8: 1 (20%)\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("showFinalVotes() doesn't contain %q:\n%s", want, got)
+		}
+	}
+	for _, bar := range []string{"█", "░", "|"} {
+		if strings.Contains(got, bar) {
+			t.Errorf("compact showFinalVotes() contains bar character %q:\n%s", bar, got)
+		}
+	}
+}
+
+// TestPercentOfPlayers tests computing the distribution percentages over the
+// players who voted and over all connected players
+func TestPercentOfPlayers(t *testing.T) {
+	points := []string{"5", "5", "8"}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    []string
+	}{
+		{"over votes", false, []string{"(66.7%)", "(33.3%)"}},
+		{"over players", true, []string{"(40.0% of 5 players)", "(20.0% of 5 players)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options.PercentOfPlayers = tt.enabled
+			defer func() { options.PercentOfPlayers = false }()
+
+			got := showFinalVotes(points, len(points), 5)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("showFinalVotes() doesn't contain %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+// setTestPlayers replaces the players of the game state. Callers must hold state.mu.
+func setTestPlayers(players map[string]*playerState) {
+	state.resetPlayers()
+	for key, player := range players {
+		state.putPlayer(key, player)
+	}
+}
+
+// stubSession is an SSH session recording what is written to it. Writes fail
+// once it's closed. Methods other than Write, Close, and PublicKey aren't
+// implemented.
+type stubSession struct {
+	ssh.Session
+	mu       sync.Mutex
+	out      bytes.Buffer
+	closed   bool
+	closeErr error
+	key      ssh.PublicKey
+}
+
+// PublicKey returns the key the session authenticated with, if any.
+func (s *stubSession) PublicKey() ssh.PublicKey {
+	return s.key
+}
+
+// Write records p, or fails when the session is closed.
+func (s *stubSession) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, io.EOF
+	}
+	return s.out.Write(p)
+}
+
+// Close marks the session as closed and returns closeErr.
+func (s *stubSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return s.closeErr
+}
+
+// isClosed reports whether the session was closed.
+func (s *stubSession) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// output returns everything written to the session.
+func (s *stubSession) output() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.out.String()
+}
+
+// TestResetSessions tests resetting active and closed sessions on shutdown
+// while their programs keep writing
+func TestResetSessions(t *testing.T) {
+	state = newGameState()
+
+	master := &stubSession{}
+	active := &stubSession{}
+	closed := &stubSession{closed: true}
+	state.masters[&syncSession{Session: master}] = "Robin"
+	state.putPlayer("alice", &playerState{name: "alice", session: &syncSession{Session: active}})
+	state.putPlayer("bob", &playerState{name: "bob", session: &syncSession{Session: closed}})
+	state.putPlayer("carol", &playerState{name: "carol"})
+
+	// Keep writing frames to the active session like its program would
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		session := state.players["alice"].session
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				session.Write([]byte("frame"))
+			}
+		}
+	}()
+
+	resetSessions()
+	close(done)
+	wg.Wait()
+
+	if !strings.Contains(master.output(), resetTerminalSequence) {
+		t.Errorf("master terminal not reset, got %q", master.output())
+	}
+	if !strings.Contains(active.output(), resetTerminalSequence) {
+		t.Errorf("player terminal reset interleaved or missing, got %q", active.output())
+	}
+	if closed.output() != "" {
+		t.Errorf("closed session got output %q", closed.output())
+	}
+}
+
+// TestClaimMaster tests that Scrum Masters share control, and that a key
+// reconnecting replaces its previous session
+func TestClaimMaster(t *testing.T) {
+	state = newGameState()
+	robinKey := newTestPublicKey(t)
+
+	robin := &stubSession{key: robinKey}
+	if stale := claimMaster(robin, "Robin"); stale != nil {
+		t.Errorf("claimMaster() first master stale = %v, want nil", stale)
+	}
+	sam := &stubSession{key: newTestPublicKey(t)}
+	if stale := claimMaster(sam, "Sam"); stale != nil {
+		t.Errorf("claimMaster() second master stale = %v, want nil", stale)
+	}
+	if stale := claimMaster(&stubSession{}, "Password"); stale != nil {
+		t.Errorf("claimMaster() keyless master stale = %v, want nil", stale)
+	}
+	if got, want := masterList(), "Password, Robin, Sam"; got != want {
+		t.Errorf("masterList() = %q, want %q", got, want)
+	}
+
+	reconnected := &stubSession{key: robinKey}
+	if stale := claimMaster(reconnected, "Robin"); stale != robin {
+		t.Errorf("claimMaster() reconnect stale = %v, want the previous session", stale)
+	}
+	if _, ok := state.masters[robin]; ok || len(state.masters) != 3 {
+		t.Errorf("masters = %v, want the previous session replaced", state.masters)
+	}
+}
+
+// TestValidateAuthorizedKeysPath tests failing fast on an authorized keys file
+// that is a directory or can't be read
+func TestValidateAuthorizedKeysPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "showdown_keys")
+	if err := os.WriteFile(file, []byte("ssh-ed25519 AAAA\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	unreadable := filepath.Join(dir, "unreadable_keys")
+	if err := os.WriteFile(unreadable, []byte("ssh-ed25519 AAAA\n"), 0o000); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"file", file, ""},
+		{"missing file", filepath.Join(dir, "missing"), ""},
+		{"directory", dir, "is a directory"},
+		{"permission denied", unreadable, "not readable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.path == unreadable && os.Geteuid() == 0 {
+				t.Skip("root reads files regardless of their permissions")
+			}
+			err := validateAuthorizedKeysPath(tt.path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateAuthorizedKeysPath() err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateAuthorizedKeysPath() err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestListenHost tests choosing the listen address over the hostname and the
+// fallback when the hostname can't be determined
+func TestListenHost(t *testing.T) {
+	hostname := func() (string, error) { return "beans.local", nil }
+	broken := func() (string, error) { return "", errors.New("no hostname") }
+	empty := func() (string, error) { return "", nil }
+
+	tests := []struct {
+		name     string
+		addr     string
+		hostname func() (string, error)
+		want     string
+		wantErr  bool
+	}{
+		{"hostname by default", "", hostname, "beans.local", false},
+		{"address overrides hostname", "0.0.0.0", hostname, "0.0.0.0", false},
+		{"address without hostname", "127.0.0.1", broken, "127.0.0.1", false},
+		{"hostname error falls back", "", broken, fallbackHost, true},
+		{"empty hostname falls back", "", empty, fallbackHost, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := listenHost(tt.addr, tt.hostname)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("listenHost(%q) err = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("listenHost(%q) = %q, want %q", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRequireKey tests that keyless players can join through keyboard-interactive
+// auth, unless a key is required
+func TestRequireKey(t *testing.T) {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		requireKey bool
+		wantErr    bool
+	}{
+		{"keyless player joins", false, false},
+		{"keyless player rejected", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &ssh.Server{Handler: func(ssh.Session) {}}
+			for _, opt := range authOptions("", tt.requireKey) {
+				if err := srv.SetOption(opt); err != nil {
+					t.Fatal(err)
+				}
+			}
+			srv.AddHostKey(signer)
+
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			go srv.Serve(l)
+			defer srv.Close()
+
+			client, err := gossh.Dial("tcp", l.Addr().String(), &gossh.ClientConfig{
+				User: "alice",
+				Auth: []gossh.AuthMethod{
+					gossh.KeyboardInteractive(func(string, string, []string, []bool) ([]string, error) {
+						return nil, nil
+					}),
+				},
+				HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+			})
+			if err == nil {
+				client.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("keyless dial err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,48 +2,106 @@ package main
 
 import (
 	"fmt"
-	"sort"
+	"io"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
 )
 
 // keyMapMaster defines the key bindings available to the Scrum Master,
-// including reveal, clear, disconnect, quit, and timer controls.
+// including reveal, sharing, reopen, re-vote, clear, skipping stories, locking
+// joins, switching decks, export, copying the results, disconnect, quit,
+// player selection, revealing one vote, whispers, announcements, the activity
+// feed, the live tally, the help toggle, and the voting and discussion timer
+// controls.
 type keyMapMaster struct {
 	Reveal     key.Binding
+	Share      key.Binding
+	Reopen     key.Binding
+	Revote     key.Binding
 	Clear      key.Binding
+	Skip       key.Binding
+	Freeze     key.Binding
+	Deck       key.Binding
+	Export     key.Binding
+	Copy       key.Binding
 	Disconnect key.Binding
 	Quit       key.Binding
+	Up         key.Binding
+	Down       key.Binding
+	RevealOne  key.Binding
+	Whisper    key.Binding
+	Announce   key.Binding
+	Feed       key.Binding
+	Tally      key.Binding
+	Help       key.Binding
+	Discuss    key.Binding
 	One        key.Binding
 	Three      key.Binding
 	Six        key.Binding
 }
 
 var (
-	state = &gameState{
-		players: make(map[string]*playerState),
-	}
+	state = newGameState()
 
+	// Timer presets use function keys so they never collide with card values
 	timerDurations = map[string]time.Duration{
-		"1": 15 * time.Second,
-		"3": 30 * time.Second,
-		"6": 60 * time.Second,
+		"f1": 15 * time.Second,
+		"f3": 30 * time.Second,
+		"f6": 60 * time.Second,
 	}
 
+	discussionDuration = 2 * time.Minute
+
 	keysMaster = keyMapMaster{
 		Reveal: key.NewBinding(
 			key.WithKeys("r"),
 			key.WithHelp("r", "reveal"),
 		),
+		Share: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "share results with players"),
+		),
+		Reopen: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "reopen voting"),
+		),
+		Revote: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "re-vote"),
+		),
 		Clear: key.NewBinding(
 			key.WithKeys("c"),
 			key.WithHelp("c", "clear score"),
 		),
+		Skip: key.NewBinding(
+			key.WithKeys("."),
+			key.WithHelp(".", "skip story"),
+		),
+		Freeze: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "lock/unlock joins"),
+		),
+		Deck: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "switch deck"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "export markdown"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy results"),
+		),
 		Disconnect: key.NewBinding(
 			key.WithKeys("d"),
 			key.WithHelp("d", "disconnect players"),
@@ -52,17 +110,54 @@ var (
 			key.WithKeys("q", "esc", "ctrl+c"),
 			key.WithHelp("q", "quit"),
 		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "previous player"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "next player"),
+		),
+		RevealOne: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "show selected vote"),
+		),
+		Whisper: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "whisper to selected"),
+		),
+		Announce: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "announce to everyone"),
+		),
+		Feed: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "toggle activity feed"),
+		),
+		Tally: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "toggle live tally"),
+		),
+		Discuss: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "discussion timer"),
+		),
+		// Help uses h since ? is a card of the decks
+		Help: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "cycle help"),
+		),
 		One: key.NewBinding(
-			key.WithKeys("1"),
-			key.WithHelp("1", "15 seconds"),
+			key.WithKeys("f1"),
+			key.WithHelp("F1", "15 seconds"),
 		),
 		Three: key.NewBinding(
-			key.WithKeys("3"),
-			key.WithHelp("3", "30 seconds"),
+			key.WithKeys("f3"),
+			key.WithHelp("F3", "30 seconds"),
 		),
 		Six: key.NewBinding(
-			key.WithKeys("6"),
-			key.WithHelp("6", "60 seconds"),
+			key.WithKeys("f6"),
+			key.WithHelp("F6", "60 seconds"),
 		),
 	}
 
@@ -70,19 +165,121 @@ var (
 )
 
 // masterView is the Bubble Tea model for the Scrum Master interface, displaying
-// connected players, voting status, timer countdown, and voting statistics.
+// connected players, voting status, timer countdown, voting statistics, and the
+// activity feed. While copying, the results are printed to the scrollback
+// outside the alt screen until a key is pressed. A facilitator uses the view
+// with the keys for revealing, clearing, and the timers only.
 type masterView struct {
-	revealed bool
-	timer    *time.Timer
-	endTime  time.Time
-	duration time.Duration
-	keys     keyMapMaster
-	help     help.Model
+	revealed    bool
+	cursor      int
+	status      string
+	input       textinput.Model
+	inputFor    inputMode
+	target      string
+	showFeed    bool
+	showTally   bool
+	copying     bool
+	facilitator bool
+	out         io.Writer
+	keys        keyMapMaster
+	help        help.Model
+	helpMode    helpMode
+
+	// countdown is the number of suspense ticks left before the revealed
+	// votes are shown.
+	countdown int
+}
+
+// helpMode is how much of the key help the master view shows, cycled with the
+// help key to make room for long player lists.
+type helpMode int
+
+const (
+	helpFull helpMode = iota
+	helpShort
+	helpHidden
+)
+
+// cycleHelp switches from full to short to hidden help and back to full.
+func (m *masterView) cycleHelp() {
+	m.helpMode = (m.helpMode + 1) % 3
+	m.help.ShowAll = m.helpMode == helpFull
+}
+
+// inputMode tells what the master's text input is currently used for.
+type inputMode int
+
+const (
+	inputNone inputMode = iota
+	inputWhisper
+	inputAnnounce
+)
+
+// timerExpiredMsg is sent when a timer reaches zero. An expired voting timer
+// triggers automatic reveal of all player votes; a discussion timer does not.
+// Messages whose id no longer matches state.timerID were cancelled or replaced,
+// possibly by another master, and are ignored.
+type timerExpiredMsg struct {
+	id   int
+	kind timerKind
+}
+
+// autoClearMsg is sent when the auto-clear delay after a reveal has passed.
+// Messages whose id no longer matches state.autoClearID were cancelled,
+// possibly by another master, and are ignored.
+type autoClearMsg struct {
+	id int
+}
+
+// scheduleAutoClear cancels any pending auto-clear and, when -auto-clear is
+// set, returns the command that clears the round after the delay.
+func scheduleAutoClear() tea.Cmd {
+	state.mu.Lock()
+	state.autoClearID++
+	id := state.autoClearID
+	state.mu.Unlock()
+
+	if options.AutoClear <= 0 {
+		return nil
+	}
+	return tea.Tick(time.Duration(options.AutoClear)*time.Second, func(time.Time) tea.Msg {
+		return autoClearMsg{id: id}
+	})
+}
+
+// cancelAutoClear stops any pending auto-clear so its message is ignored.
+func cancelAutoClear() {
+	state.mu.Lock()
+	state.autoClearID++
+	state.mu.Unlock()
+}
+
+// currentGeneration reports whether id still matches the counter of the
+// timer, auto-clear or reveal it was issued for.
+func currentGeneration(id int, counter *int) bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return id == *counter
 }
 
-// timerExpiredMsg is sent when the voting timer reaches zero, triggering
-// automatic reveal of all player votes.
-type timerExpiredMsg struct{}
+// revealTickMsg advances the suspense countdown of the reveal with the given id.
+type revealTickMsg struct {
+	id int
+}
+
+const (
+	// revealCountdown is the number the suspense countdown starts from.
+	revealCountdown = 3
+	// revealTickInterval is the time between the numbers of the countdown.
+	revealTickInterval = 400 * time.Millisecond
+)
+
+// revealTick returns a command that sends the next revealTickMsg.
+func revealTick(id int) tea.Cmd {
+	return tea.Tick(revealTickInterval, func(time.Time) tea.Msg {
+		return revealTickMsg{id: id}
+	})
+}
 
 // tickMsg and tickEvery moved to main.go for shared access
 
@@ -93,6 +290,18 @@ func newMasterView() masterView {
 		revealed: false,
 		keys:     keysMaster,
 		help:     help.New(),
+		input:    textinput.New(),
+	}
+	m.input.CharLimit = maxMessageLength
+	m.input.Cursor.Style = focusStyle
+	m.input.PromptStyle = focusStyle
+
+	if options.NoTimer {
+		m.keys.disableTimers()
+	}
+	// Votes are only shared separately in a blind reveal
+	if !options.BlindReveal {
+		m.keys.Share.SetEnabled(false)
 	}
 
 	// default show full help information
@@ -100,18 +309,52 @@ func newMasterView() masterView {
 	return m
 }
 
+// disableTimers disables the timer bindings, which also hides them from the
+// help view and leaves their keys unmatched.
+func (k *keyMapMaster) disableTimers() {
+	for _, binding := range []*key.Binding{&k.One, &k.Three, &k.Six, &k.Discuss} {
+		binding.SetEnabled(false)
+	}
+}
+
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
 // of the key.Map interface.
 func (k keyMapMaster) ShortHelp() []key.Binding {
-	return []key.Binding{k.One, k.Three, k.Six, k.Reveal, k.Clear, k.Disconnect, k.Quit}
+	return []key.Binding{
+		k.One,
+		k.Three,
+		k.Six,
+		k.Discuss,
+		k.Reveal,
+		k.Share,
+		k.Reopen,
+		k.Revote,
+		k.Clear,
+		k.Skip,
+		k.Freeze,
+		k.Deck,
+		k.Export,
+		k.Copy,
+		k.Disconnect,
+		k.Quit,
+		k.Up,
+		k.Down,
+		k.RevealOne,
+		k.Whisper,
+		k.Announce,
+		k.Feed,
+		k.Tally,
+		k.Help,
+	}
 }
 
 // FullHelp returns keybindings for the expanded help view. It's part of the
 // key.Map interface.
 func (k keyMapMaster) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.One, k.Three, k.Six},
-		{k.Reveal, k.Clear, k.Disconnect, k.Quit},
+		{k.One, k.Three, k.Six, k.Discuss},
+		{k.Reveal, k.Share, k.Reopen, k.Revote, k.Clear, k.Skip, k.Freeze, k.Deck, k.Export, k.Copy, k.Disconnect, k.Quit},
+		{k.Up, k.Down, k.RevealOne, k.Whisper, k.Announce, k.Feed, k.Tally, k.Help},
 	}
 }
 
@@ -122,40 +365,463 @@ func (m masterView) Init() tea.Cmd {
 }
 
 // startTimer returns a Bubble Tea command that waits for the specified duration
-// and then sends a timerExpiredMsg to trigger automatic vote reveal.
-func startTimer(duration time.Duration) tea.Cmd {
+// and then sends a timerExpiredMsg with the given id and kind.
+func startTimer(id int, kind timerKind, duration time.Duration) tea.Cmd {
 	return func() tea.Msg {
 		time.Sleep(duration)
-		return timerExpiredMsg{}
+		return timerExpiredMsg{id: id, kind: kind}
 	}
 }
 
-// quitPlayers disconnects all connected player sessions by resetting their
-// terminals and closing their SSH connections, then clears the players map.
+// setTimer cancels any running timer, publishes the new one in the game state
+// so both views can show the countdown, and returns the command that fires
+// its expiry.
+func setTimer(kind timerKind, duration time.Duration) tea.Cmd {
+	state.mu.Lock()
+	state.timerID++
+	id := state.timerID
+	state.timerKind = kind
+	state.timerEnd = time.Now().Add(duration)
+	state.mu.Unlock()
+
+	return startTimer(id, kind, duration)
+}
+
+// cancelTimer stops any running timer so its expiry is ignored and clears the
+// countdown from the game state.
+func cancelTimer() {
+	state.mu.Lock()
+	state.timerID++
+	state.timerEnd = time.Time{}
+	state.mu.Unlock()
+}
+
+// quitPlayers removes all players from the game and disconnects their SSH
+// sessions by resetting their terminals and closing their connections. The
+// sessions are disconnected in the background, so a half-dead connection
+// blocking on write neither holds up the Scrum Master nor keeps the player in
+// the game. Callers must hold state.mu.
 func quitPlayers() {
 	for _, player := range state.players {
-		// Reset terminal before closing session
-		resetTerminal(player.session)
-		player.session.Close()
+		if player.whispers != nil {
+			close(player.whispers)
+		}
+		// Web players have no session and notice their removal on their own
+		if player.session != nil {
+			go disconnectSession(player.name, player.session)
+		}
+	}
+	if len(state.players) > 0 {
+		logActivity("all players were disconnected")
 	}
-	state.players = make(map[string]*playerState)
+	state.resetPlayers()
 }
 
 // clearPlayerState resets the game state for a new voting round by clearing
-// the revealed flag and resetting all player selections and points.
+// the revealed flags, restarting the round and voting start times, and
+// resetting all player selections, points, confidence, vote times, reactions,
+// early reveals, and ready check-ins. A reveal still waiting for sealed votes
+// is announced with the votes that were opened.
 func clearPlayerState() {
 	state.mu.Lock()
-	state.revealed = false
+	settleReveal()
+	state.masterRevealed = false
+	state.playersRevealed = false
+	state.roundStart = time.Now()
+	state.votingStart = time.Time{}
 	for _, player := range state.players {
 		player.points = ""
 		player.selected = false
+		player.risk = ""
+		player.riskSelected = false
+		player.confidence = confidenceNone
+		player.votedAt = time.Time{}
+		player.reaction = ""
+		player.ready = false
+		player.revealed = false
+		player.abstained = false
+		player.commit = ""
+		player.changedAt = time.Time{}
+		player.voteChanges = 0
+	}
+	events.emit(event{Type: "clear", Round: state.round})
+	state.mu.Unlock()
+}
+
+// abstainSilent records "?" for every player who hasn't voted or sealed a
+// vote, marked as abstained so it's told apart from a chosen "?".
+func abstainSilent() {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for _, key := range sortedPlayerKeys() {
+		player := state.players[key]
+		if player.selected || player.commit != "" {
+			continue
+		}
+		player.points = "?"
+		player.selected = true
+		player.abstained = true
+		if options.Risk && !player.riskSelected {
+			player.risk = "?"
+			player.riskSelected = true
+		}
+		logActivity("%s abstained", player.name)
+	}
+}
+
+// revealVotes reveals the votes of the current round. The first reveal of each
+// round counts it as played; reveals after reopening or re-voting do not. The
+// first reveal of each attempt writes the estimate of the current story back
+// to the tracker the backlog came from. With -blind-reveal the players only see
+// the votes once the master shares them. It returns false when the votes were
+// already revealed, for example by another Scrum Master at the same time.
+func revealVotes() bool {
+	return revealVotesAfter(0)
+}
+
+// revealVotesAfter reveals the votes like revealVotes, but keeps them from the
+// players until the suspense countdown of the given length has ended.
+func revealVotesAfter(suspense time.Duration) bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.masterRevealed {
+		return false
+	}
+	state.masterRevealed = true
+	state.playersRevealed = !options.BlindReveal
+	state.revealAt = time.Now().Add(suspense)
+	announceReveal(recordRound(time.Now()))
+	if state.lastPlayedRound != state.round {
+		state.lastPlayedRound = state.round
+		state.roundsPlayed++
+	}
+	return true
+}
+
+// voteNotes returns what the master sees next to a revealed vote: that it was
+// abstained, or a * with the time of the last change when the player changed
+// or retracted their card, its risk card, and confidence level.
+func voteNotes(vote playerVote) string {
+	if vote.abstained {
+		return " " + t("vote.abstained")
+	}
+	var notes string
+	if vote.voteChanges > 0 {
+		notes += " * " + vote.changedAt.Format("15:04:05")
+	}
+	if vote.riskSelected {
+		notes += " " + t("vote.risk", vote.risk)
+	}
+	if vote.confidence != confidenceNone {
+		notes += " " + t("vote.confidence", vote.confidence.label())
+	}
+	return notes
+}
+
+// revealRefresh returns a command that redraws a player view once the
+// running suspense countdown has ended, or nil when none is running.
+func revealRefresh() tea.Cmd {
+	state.mu.RLock()
+	left := state.revealAt.Sub(time.Now())
+	running := state.playersRevealed && left > 0
+	state.mu.RUnlock()
+	if !running {
+		return nil
+	}
+	return tea.Tick(left, func(time.Time) tea.Msg {
+		return revealTickMsg{}
+	})
+}
+
+// resultsShared reports whether the players see the votes of the current round:
+// once they are shared and the suspense countdown of the reveal has ended.
+// Callers must hold state.mu.
+func resultsShared(now time.Time) bool {
+	return state.playersRevealed && countdownLeft(now) == 0
+}
+
+// countdownLeft returns the number of suspense ticks left before the players
+// see the votes of the latest reveal. Callers must hold state.mu.
+func countdownLeft(now time.Time) int {
+	left := state.revealAt.Sub(now)
+	if left <= 0 {
+		return 0
+	}
+	return int((left + revealTickInterval - 1) / revealTickInterval)
+}
+
+// revealingView renders the suspense countdown with the given number of ticks
+// left, counting down from revealCountdown: "Revealing... 3 2" with 2 left.
+func revealingView(countdown int) string {
+	var s strings.Builder
+	s.WriteString(t("master.revealing"))
+	for n := revealCountdown; n >= countdown; n-- {
+		fmt.Fprintf(&s, " %d", n)
+	}
+	return focusStyle.Render(s.String())
+}
+
+// shareVotes shows the votes the Scrum Master revealed to the players as well.
+// It reports false, doing nothing, before the reveal or once they are shared.
+func shareVotes() bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.masterRevealed || state.playersRevealed {
+		return false
+	}
+	state.playersRevealed = true
+	return true
+}
+
+// selectedPlayer returns the player at the cursor position in the sorted
+// player list, or nil when there are no players. Callers must hold state.mu.
+func selectedPlayer(cursor int) *playerState {
+	keys := sortedPlayerKeys()
+	if len(keys) == 0 {
+		return nil
+	}
+	cursor = min(max(cursor, 0), len(keys)-1)
+	return state.players[keys[cursor]]
+}
+
+// setAnnouncement sets the announcement shown at the top of every player view,
+// including players joining later. An empty text clears it.
+func setAnnouncement(text string) {
+	state.mu.Lock()
+	state.announcement = text
+	state.mu.Unlock()
+}
+
+// versionFooter returns the server version and uptime, like
+// "Showdown v1.2.3 (abc1234) • up 15m", to confirm which build is running.
+func versionFooter(now time.Time) string {
+	uptime := now.Sub(serverStart).Truncate(time.Minute)
+	hours, minutes := int(uptime.Hours()), int(uptime.Minutes())%60
+	if hours > 0 {
+		return t("master.version", serverVersion, fmt.Sprintf("%dh%dm", hours, minutes))
+	}
+	return t("master.version", serverVersion, fmt.Sprintf("%dm", minutes))
+}
+
+// readyCount returns how many players checked in as ready and the number of
+// connected players, leaving out those who lost their connection. Callers must
+// hold state.mu.
+func readyCount() (int, int) {
+	ready, total := 0, 0
+	for _, player := range state.players {
+		if player.offline {
+			continue
+		}
+		total++
+		if player.vote().ready {
+			ready++
+		}
+	}
+	return ready, total
+}
+
+// missingVoters returns how many more players need to vote before the votes
+// may be revealed with -min-voters. Callers must hold state.mu.
+func missingVoters() int {
+	voted := 0
+	for _, player := range state.players {
+		if player.vote().voted() {
+			voted++
+		}
+	}
+	return max(options.MinVoters-voted, 0)
+}
+
+// missingVotersStatus tells the Scrum Master how many more votes are needed,
+// like "Need 2 more votes to reveal".
+func missingVotersStatus(missing int) string {
+	return plural("master.needVotes", missing)
+}
+
+// liveTally returns how many players selected each card, like "5: 2, 8: 1",
+// without their names. Cards are in deck order and players who haven't voted
+// aren't counted. It's only shown to the Scrum Master, so players can't see
+// the votes before the reveal. Callers must hold state.mu.
+func liveTally() string {
+	counts := voteCounts()
+
+	var parts []string
+	for _, card := range activeDeck() {
+		if n := counts[card]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", card, n))
+			delete(counts, card)
+		}
+	}
+	// Votes for cards that are no longer in the deck
+	for _, card := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%s: %d", card, counts[card]))
 	}
+	return strings.Join(parts, ", ")
+}
+
+// capacityBarWidth is the width of the capacity bar in cells.
+const capacityBarWidth = 10
+
+// playerCap returns how many players may join: the -max-players limit when
+// set, otherwise maxPlayers.
+func playerCap() int {
+	if options.MaxPlayers > 0 {
+		return options.MaxPlayers
+	}
+	return maxPlayers
+}
+
+// capacityBadge renders how full the room is, like "Players: 8/20" with a bar,
+// or nothing without a configured limit.
+func capacityBadge(players, limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	filled := min((players*capacityBarWidth+limit-1)/limit, capacityBarWidth)
+	bar := focusStyle.Render(strings.Repeat("█", filled)) + helpStyle(strings.Repeat("░", capacityBarWidth-filled))
+	return t("master.capacity", players, limit) + " " + bar
+}
+
+// waitingOn returns the names of the connected players who haven't voted or
+// sealed a vote yet, sorted. Callers must hold state.mu.
+func waitingOn() []string {
+	var names []string
+	for _, key := range sortedPlayerKeys() {
+		player := state.players[key]
+		if vote := player.vote(); !player.offline && !vote.voted() {
+			names = append(names, player.name)
+		}
+	}
+	return names
+}
+
+// voteCounts returns how many players selected each card. Callers must hold
+// state.mu.
+func voteCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, player := range state.players {
+		if vote := player.vote(); vote.selected {
+			counts[vote.points]++
+		}
+	}
+	return counts
+}
+
+// sparkBlocks are the block characters of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders counts as block characters scaled to the highest count,
+// like "▁▃█▂" for 1, 3, 8, and 2. Zero counts are blank, and without any count
+// the sparkline is empty.
+func sparkline(counts []int) string {
+	highest := slices.Max(append([]int{0}, counts...))
+	if highest == 0 {
+		return ""
+	}
+
+	var s strings.Builder
+	for _, count := range counts {
+		if count <= 0 {
+			s.WriteRune(' ')
+			continue
+		}
+		// Round up, so any vote shows at least the lowest block
+		level := (count*len(sparkBlocks)+highest-1)/highest - 1
+		s.WriteRune(sparkBlocks[level])
+	}
+	return s.String()
+}
+
+// voteSparkline renders the votes of the round as a sparkline over the cards
+// of the deck, in deck order. Before the reveal it only shows with the live
+// tally turned on. Callers must hold state.mu.
+func voteSparkline() string {
+	counts := voteCounts()
+	deck := activeDeck()
+	perCard := make([]int, len(deck))
+	for i, card := range deck {
+		perCard[i] = counts[card]
+	}
+	return sparkline(perCard)
+}
+
+// nextRound clears the player state and starts a new round, resetting the
+// attempt counter.
+func nextRound() {
+	clearPlayerState()
+	state.mu.Lock()
+	state.round++
+	state.attempt = 1
+	if state.storyIndex < len(state.backlog) {
+		state.storyIndex++
+	}
+	state.mu.Unlock()
+}
+
+// revote clears the player state for another attempt at the current round,
+// so players vote again on the same story after discussion.
+func revote() {
+	clearPlayerState()
+	state.mu.Lock()
+	state.attempt++
 	state.mu.Unlock()
 }
 
+// updateInput handles keys while the master types a message: enter sends it,
+// esc cancels, and any other key edits the text. Sending an empty
+// announcement clears the current one.
+func (m masterView) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.inputFor = inputNone
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		text := strings.TrimSpace(m.input.Value())
+		mode := m.inputFor
+		m.inputFor = inputNone
+		m.input.Blur()
+
+		switch mode {
+		case inputAnnounce:
+			setAnnouncement(text)
+			if text == "" {
+				m.status = t("master.announceCleared")
+			} else {
+				m.status = t("master.announceSent")
+			}
+		case inputWhisper:
+			if text == "" {
+				return m, nil
+			}
+
+			state.mu.RLock()
+			player, exists := state.players[playerKey(m.target)]
+			delivered := exists && whisper(player, text)
+			state.mu.RUnlock()
+
+			if delivered {
+				m.status = t("master.whispered", m.target)
+			} else {
+				m.status = t("master.whisperFailed", m.target)
+			}
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
 // Update handles all incoming messages for the master view including keyboard
-// input for reveal/clear/disconnect/quit actions, timer key presses, window
-// resize events, and timer expiration. Implements the tea.Model interface.
+// input for reveal/reopen/re-vote/clear/export/disconnect/quit actions, player
+// selection, early reveal, whispers, and announcements, voting and
+// discussion timer key presses, window resize events, and timer expiration.
+// Implements the tea.Model interface.
 func (m masterView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -164,51 +830,287 @@ func (m masterView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.help.Width = msg.Width
 
 	case tea.KeyMsg:
+		// While typing a message all keys go to the text input
+		if m.inputFor != inputNone {
+			return m.updateInput(msg)
+		}
+		// Any key returns from the copied results to the alt screen
+		if m.copying {
+			m.copying = false
+			return m, tea.Batch(tea.EnterAltScreen, tickEvery())
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Quit):
-			quitPlayers()
-			state.masterConn = nil
+			// Facilitators and co-masters leave without ending the session
+			// for everyone, the last Scrum Master ends it
+			if m.facilitator {
+				return m, tea.Quit
+			}
+			state.mu.Lock()
+			if len(state.masters) <= 1 {
+				quitPlayers()
+			}
+			state.mu.Unlock()
 
 			return m, tea.Quit
 		case key.Matches(msg, m.keys.Reveal):
+			state.mu.RLock()
+			missing := missingVoters()
+			state.mu.RUnlock()
+			if missing > 0 {
+				m.status = missingVotersStatus(missing)
+				return m, nil
+			}
+			m.status = ""
+			var suspense time.Duration
+			if !options.NoSuspense {
+				suspense = revealCountdown * revealTickInterval
+			}
+			if !revealVotesAfter(suspense) {
+				return m, tickEvery()
+			}
+			// A blind reveal auto-clears once the votes are shared
+			var autoClear tea.Cmd
+			if !options.BlindReveal {
+				autoClear = scheduleAutoClear()
+			}
+			if options.NoSuspense {
+				return m, tea.Batch(tickEvery(), autoClear)
+			}
+
+			// Count down before the master view shows the votes
 			state.mu.Lock()
-			state.revealed = true
+			state.revealID++
+			id := state.revealID
 			state.mu.Unlock()
+			m.countdown = revealCountdown
+			return m, tea.Batch(tickEvery(), revealTick(id), autoClear)
+		case key.Matches(msg, m.keys.Share):
+			if !shareVotes() {
+				return m, nil
+			}
+
+			return m, tea.Batch(tickEvery(), scheduleAutoClear())
+		case key.Matches(msg, m.keys.Reopen):
+			state.mu.Lock()
+			state.masterRevealed = false
+			state.playersRevealed = false
+			state.mu.Unlock()
+			cancelAutoClear()
+
+			return m, tickEvery()
+		case key.Matches(msg, m.keys.Revote):
+			revote()
+			cancelTimer()
+			cancelAutoClear()
 
 			return m, tickEvery()
 		case key.Matches(msg, m.keys.Clear):
-			clearPlayerState()
-			m.timer = nil
+			nextRound()
+			cancelTimer()
+			cancelAutoClear()
 
 			return m, tickEvery()
+		case key.Matches(msg, m.keys.Skip):
+			// Skip the current story, or re-queue the skipped ones once the backlog is done
+			if skipStory() {
+				cancelTimer()
+				cancelAutoClear()
+			} else if n := requeueSkipped(); n > 0 {
+				m.status = plural("master.requeued", n)
+			}
+
+			return m, tickEvery()
+		case key.Matches(msg, m.keys.Freeze):
+			if toggleFrozen() {
+				m.status = t("master.joinsLocked")
+			} else {
+				m.status = t("master.joinsUnlocked")
+			}
+
+			return m, nil
+		case key.Matches(msg, m.keys.Deck):
+			name := nextDeckPreset()
+			cards, _ := deckPreset(name)
+			setDeck(cards)
+			cancelTimer()
+			cancelAutoClear()
+			m.status = t("master.deck", name, strings.Join(cards, " "))
+
+			return m, tickEvery()
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+			return m, nil
+		case key.Matches(msg, m.keys.Down):
+			state.mu.RLock()
+			count := len(state.players)
+			state.mu.RUnlock()
+			if m.cursor < count-1 {
+				m.cursor++
+			}
+
+			return m, nil
+		case key.Matches(msg, m.keys.RevealOne):
+			state.mu.Lock()
+			if player := selectedPlayer(m.cursor); player != nil {
+				player.revealed = true
+			}
+			state.mu.Unlock()
+
+			return m, nil
+		case key.Matches(msg, m.keys.Whisper):
+			state.mu.RLock()
+			player := selectedPlayer(m.cursor)
+			state.mu.RUnlock()
+			if player == nil {
+				return m, nil
+			}
+
+			m.inputFor = inputWhisper
+			m.target = player.name
+			m.input.Prompt = t("master.whisperPrompt", player.name)
+			m.input.SetValue("")
+
+			return m, m.input.Focus()
+		case key.Matches(msg, m.keys.Announce):
+			state.mu.RLock()
+			current := state.announcement
+			state.mu.RUnlock()
+
+			m.inputFor = inputAnnounce
+			m.input.Prompt = t("master.announcePrompt")
+			m.input.SetValue(current)
+			m.input.CursorEnd()
+
+			return m, m.input.Focus()
+		case key.Matches(msg, m.keys.Feed):
+			m.showFeed = !m.showFeed
+
+			return m, nil
+		case key.Matches(msg, m.keys.Tally):
+			m.showTally = !m.showTally
+
+			return m, nil
+		case key.Matches(msg, m.keys.Help):
+			m.cycleHelp()
+
+			return m, nil
+		case key.Matches(msg, m.keys.Export):
+			state.mu.RLock()
+			revealed := state.masterRevealed
+			state.mu.RUnlock()
+
+			if !revealed {
+				m.status = t("master.exportHidden")
+				return m, nil
+			}
+			filename, err := exportMarkdown()
+			if err != nil {
+				log.Error("failed to export results", "error", err)
+				m.status = t("master.exportFailed", err)
+				return m, nil
+			}
+			m.status = t("master.exported", filename)
+
+			return m, nil
+		case key.Matches(msg, m.keys.Copy):
+			state.mu.RLock()
+			revealed := state.masterRevealed
+			state.mu.RUnlock()
+
+			if !revealed {
+				m.status = t("master.copyHidden")
+				return m, nil
+			}
+			// Print outside the alt screen, so the results stay in the
+			// scrollback to select with the terminal
+			m.copying = true
+			return m, tea.Sequence(tea.ExitAltScreen, tea.Println(formatPlainText(currentRoundResult())))
 		case key.Matches(msg, m.keys.Disconnect):
 			state.mu.Lock()
 			quitPlayers()
 			state.mu.Unlock()
-			m.timer = nil
+			cancelTimer()
 
 			return m, tickEvery()
 		case key.Matches(msg, m.keys.One),
 			key.Matches(msg, m.keys.Three),
 			key.Matches(msg, m.keys.Six):
+			if options.RequireReady {
+				state.mu.RLock()
+				ready, total := readyCount()
+				state.mu.RUnlock()
+				if ready < total || total == 0 {
+					m.status = t("master.waitReady")
+					return m, nil
+				}
+			}
+			m.status = ""
 			clearPlayerState()
 			// Start timer with selected duration
 			duration := timerDurations[msg.String()]
-			m.duration = duration
-			m.endTime = time.Now().Add(duration)
 
 			return m, tea.Batch(
 				tickEvery(),
-				startTimer(duration),
+				setTimer(votingTimer, duration),
+			)
+		case key.Matches(msg, m.keys.Discuss):
+			return m, tea.Batch(
+				tickEvery(),
+				setTimer(discussionTimer, discussionDuration),
 			)
 		}
 	case tickMsg:
 		return m, tickEvery()
+	case revealTickMsg:
+		// Ignore the ticks of an earlier reveal
+		if !currentGeneration(msg.id, &state.revealID) || m.countdown == 0 {
+			return m, nil
+		}
+		m.countdown--
+		if m.countdown > 0 {
+			return m, revealTick(msg.id)
+		}
+		return m, nil
 	case timerExpiredMsg:
+		// Ignore timers that were cancelled or replaced
+		if !currentGeneration(msg.id, &state.timerID) {
+			return m, nil
+		}
+		if msg.kind == votingTimer {
+			if options.TimeoutAbstain {
+				abstainSilent()
+			}
+			// Time's up, so the votes are revealed even when too few voted
+			state.mu.RLock()
+			missing := missingVoters()
+			state.mu.RUnlock()
+			if missing > 0 {
+				m.status = t("master.timeUpFewVotes", options.MinVoters)
+			}
+			revealVotes()
+			// A blind reveal auto-clears once the votes are shared
+			if !options.BlindReveal {
+				return m, tea.Batch(tickEvery(), ringBell(m.out), scheduleAutoClear())
+			}
+		}
+		return m, tea.Batch(tickEvery(), ringBell(m.out))
+	case autoClearMsg:
+		// Ignore auto-clears that were cancelled by a manual action
+		if !currentGeneration(msg.id, &state.autoClearID) {
+			return m, nil
+		}
+		// Keep the revealed round in the history before clearing it
 		state.mu.Lock()
-		state.revealed = true
+		recordRound(time.Now())
 		state.mu.Unlock()
-		m.timer = nil
+
+		nextRound()
+		cancelTimer()
 		return m, tickEvery()
 	}
 	return m, nil
@@ -218,71 +1120,150 @@ func (m masterView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // list of connected players with their voting status, voting progress,
 // and statistics when votes are revealed. Implements the tea.Model interface.
 func (m masterView) View() string {
+	if m.copying {
+		return helpStyle(t("master.copied")) + "\n"
+	}
+
 	state.mu.RLock()
 	defer state.mu.RUnlock()
 
 	var s strings.Builder
-	s.WriteString("🎲 Showdown - Scrum Master\n\n")
+	if m.facilitator {
+		s.WriteString(t("master.facilitator") + "\n\n")
+	} else {
+		s.WriteString(t("master.title") + "\n\n")
+	}
+	s.WriteString(t("master.round", state.round, state.attempt, state.roundsPlayed))
+	// The spread only shows before the reveal with the live tally turned on
+	showSpread := m.showTally || state.masterRevealed && m.countdown == 0
+	if spark := voteSparkline(); spark != "" && showSpread {
+		s.WriteString("  " + labelStyle.Render(spark))
+	}
+	s.WriteString("\n\n")
+	s.WriteString(storyView())
+	if state.announcement != "" {
+		s.WriteString("📢 " + state.announcement + "\n\n")
+	}
 
 	// Show timer if active
-	if !m.endTime.IsZero() {
-		remaining := time.Until(m.endTime)
-		if remaining > 0 {
-			s.WriteString(fmt.Sprintf("⏱  Timer: %02d:%02d\n\n",
-				int(remaining.Minutes()),
-				int(remaining.Seconds())%60))
-		} else {
-			s.WriteString("⏱  Time's up!\n\n")
-		}
+	s.WriteString(timerView())
+	if state.frozen {
+		s.WriteString(t("master.locked") + "\n\n")
 	}
 
+	if badge := capacityBadge(connectedPlayers(), options.MaxPlayers); badge != "" {
+		s.WriteString(badge + "\n")
+	}
 	if len(state.players) == 0 {
-		s.WriteString("Waiting for players to join...\n")
+		s.WriteString(t("master.waitingPlayers") + "\n")
 	} else {
-		s.WriteString(fmt.Sprintf("Connected Players: %d\n\n", len(state.players)))
+		s.WriteString(t("master.connected", len(state.players)) + "\n")
+		ready, total := readyCount()
+		s.WriteString(t("master.ready", ready, total) + "\n\n")
 
-		// Sort players by name for consistent display
-		names := make([]string, 0, len(state.players))
-		for name := range state.players {
-			names = append(names, name)
-		}
-		sort.Strings(names)
+		// Keep the votes hidden during the suspense countdown of a reveal
+		revealed := state.masterRevealed && m.countdown == 0
+		suspense := state.masterRevealed && m.countdown > 0
+
+		// Players are kept sorted by name for consistent display
+		names := sortedPlayerKeys()
+		cursor := min(max(m.cursor, 0), len(names)-1)
 
-		// Display players
-		s.WriteString("Players:\n")
-		for _, name := range names {
+		// Display players, marking the selected one
+		s.WriteString(t("master.players") + "\n")
+		for i, name := range names {
 			player := state.players[name]
-			if state.revealed {
-				s.WriteString(fmt.Sprintf("• %s: %s\n", name, player.points))
+			vote := player.vote()
+			bullet := "•"
+			if i == cursor {
+				bullet = "›"
+			}
+			displayName := lipgloss.NewStyle().Foreground(player.color).Render(player.name)
+			if vote.reaction != "" {
+				displayName += " " + vote.reaction
+			}
+			if player.offline {
+				displayName += " " + t("master.offline")
+			}
+			if revealed {
+				s.WriteString(fmt.Sprintf("%s %s: %s%s\n", bullet, displayName, vote.points, voteNotes(vote)))
+			} else if vote.revealed && vote.selected {
+				s.WriteString(fmt.Sprintf("%s %s: %s %s\n", bullet, displayName, vote.points, t("master.shownEarly")))
 			} else {
-				if player.selected {
-					s.WriteString(fmt.Sprintf("• %s: ✓\n", name))
+				if vote.complete() {
+					s.WriteString(fmt.Sprintf("%s %s: ✓\n", bullet, displayName))
+				} else if vote.committed {
+					s.WriteString(fmt.Sprintf("%s %s: 🔒\n", bullet, displayName))
 				} else {
-					s.WriteString(fmt.Sprintf("• %s: waiting...\n", name))
+					s.WriteString(fmt.Sprintf("%s %s: %s\n", bullet, displayName, t("master.waiting")))
 				}
 			}
 		}
 
 		// Calculate voting progress
-		voted := 0
+		voted, complete := 0, 0
 		var points []string
+		var levels []confidence
 		for _, player := range state.players {
-			if player.selected {
+			vote := player.vote()
+			if vote.complete() {
+				complete++
+			}
+			if vote.selected {
 				voted++
-				points = append(points, player.points)
+				points = append(points, vote.points)
+				levels = append(levels, vote.confidence)
 			}
 		}
 
 		// Display statistics when revealed key is pressed and votes are available
-		if state.revealed && voted > 0 {
-			s.WriteString(showFinalVotes(points, voted))
+		if suspense {
+			s.WriteString("\n" + revealingView(m.countdown) + "\n\n")
+		} else if revealed && voted > 0 {
+			if !state.playersRevealed {
+				s.WriteString("\n" + t("master.blind") + "\n")
+			}
+			if options.Risk {
+				s.WriteString(showDualVotes(collectDimensions()))
+			} else {
+				s.WriteString(showFinalVotes(points, voted, connectedPlayers()))
+			}
+			s.WriteString(confidenceSummary(levels))
+			s.WriteString(roundTimingView())
+			s.WriteString(leaderboardView())
 		} else {
-			s.WriteString(fmt.Sprintf("\nVoting Progress: %d/%d\n\n", voted, len(state.players)))
+			s.WriteString("\n" + t("master.progress", complete, len(state.players)) + "\n")
+			// Name the stragglers once voting is underway
+			if waiting := waitingOn(); voted > 0 && len(waiting) > 0 {
+				s.WriteString(t("master.waitingOn", strings.Join(waiting, ", ")) + "\n")
+			}
+			if m.showTally && voted > 0 {
+				s.WriteString(t("master.tally", liveTally()) + "\n")
+			}
+			s.WriteString("\n")
 		}
 	}
 
-	// show help menu
-	s.WriteString(fmt.Sprintf("\n%s", m.help.View(m.keys)))
+	s.WriteString(chatView())
+	s.WriteString(activityView(m.showFeed))
+
+	if m.inputFor != inputNone {
+		s.WriteString("\n" + m.input.View() + "\n")
+	} else if m.status != "" {
+		s.WriteString("\n" + m.status + "\n")
+	}
+
+	// show help menu, or only how to bring it back when hidden
+	if m.helpMode == helpHidden {
+		s.WriteString("\n" + m.help.ShortHelpView([]key.Binding{m.keys.Help}))
+	} else {
+		s.WriteString(fmt.Sprintf("\n%s", m.help.View(m.keys)))
+	}
+	footer := versionFooter(time.Now())
+	if masters := masterList(); masters != "" {
+		footer += " • " + masters
+	}
+	s.WriteString("\n" + helpStyle(footer))
 
 	return lipgloss.NewStyle().Padding(1).Render(s.String())
 }
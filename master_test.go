@@ -1,24 +1,35 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // TestClearPlayerState tests the state reset functionality
 func TestClearPlayerState(t *testing.T) {
 	// Setup initial state
 	state.mu.Lock()
-	state.revealed = true
-	state.players["player1"] = &playerState{
+	state.masterRevealed = true
+	state.putPlayer("player1", &playerState{
 		points:   "5",
 		selected: true,
-	}
-	state.players["player2"] = &playerState{
+	})
+	state.putPlayer("player2", &playerState{
 		points:   "8",
 		selected: true,
-	}
+	})
 	state.mu.Unlock()
 
 	// Execute clear
@@ -28,7 +39,7 @@ func TestClearPlayerState(t *testing.T) {
 	state.mu.RLock()
 	defer state.mu.RUnlock()
 
-	if state.revealed {
+	if state.masterRevealed {
 		t.Errorf("clearPlayerState() revealed = true, want false")
 	}
 
@@ -54,11 +65,26 @@ func TestKeyMapMasterBindings(t *testing.T) {
 			binding: keysMaster.Reveal,
 			keys:    []string{"r"},
 		},
+		{
+			name:    "reopen binding",
+			binding: keysMaster.Reopen,
+			keys:    []string{"o"},
+		},
+		{
+			name:    "revote binding",
+			binding: keysMaster.Revote,
+			keys:    []string{"v"},
+		},
 		{
 			name:    "clear binding",
 			binding: keysMaster.Clear,
 			keys:    []string{"c"},
 		},
+		{
+			name:    "export binding",
+			binding: keysMaster.Export,
+			keys:    []string{"e"},
+		},
 		{
 			name:    "disconnect binding",
 			binding: keysMaster.Disconnect,
@@ -69,20 +95,70 @@ func TestKeyMapMasterBindings(t *testing.T) {
 			binding: keysMaster.Quit,
 			keys:    []string{"q", "esc", "ctrl+c"},
 		},
+		{
+			name:    "previous player binding",
+			binding: keysMaster.Up,
+			keys:    []string{"up", "k"},
+		},
+		{
+			name:    "next player binding",
+			binding: keysMaster.Down,
+			keys:    []string{"down", "j"},
+		},
+		{
+			name:    "show selected vote binding",
+			binding: keysMaster.RevealOne,
+			keys:    []string{"s"},
+		},
+		{
+			name:    "whisper binding",
+			binding: keysMaster.Whisper,
+			keys:    []string{"w"},
+		},
+		{
+			name:    "announce binding",
+			binding: keysMaster.Announce,
+			keys:    []string{"a"},
+		},
+		{
+			name:    "activity feed binding",
+			binding: keysMaster.Feed,
+			keys:    []string{"f"},
+		},
+		{
+			name:    "lock joins binding",
+			binding: keysMaster.Freeze,
+			keys:    []string{"l"},
+		},
+		{
+			name:    "switch deck binding",
+			binding: keysMaster.Deck,
+			keys:    []string{"p"},
+		},
+		{
+			name:    "live tally binding",
+			binding: keysMaster.Tally,
+			keys:    []string{"t"},
+		},
+		{
+			name:    "discussion timer binding",
+			binding: keysMaster.Discuss,
+			keys:    []string{"g"},
+		},
 		{
 			name:    "one minute timer binding",
 			binding: keysMaster.One,
-			keys:    []string{"1"},
+			keys:    []string{"f1"},
 		},
 		{
 			name:    "three minute timer binding",
 			binding: keysMaster.Three,
-			keys:    []string{"3"},
+			keys:    []string{"f3"},
 		},
 		{
 			name:    "six minute timer binding",
 			binding: keysMaster.Six,
-			keys:    []string{"6"},
+			keys:    []string{"f6"},
 		},
 	}
 
@@ -101,11 +177,33 @@ func TestKeyMapMasterBindings(t *testing.T) {
 	}
 }
 
+// TestKeyMapMasterUniqueKeys tests that no key is bound twice and that no card
+// of the deck is a master key
+func TestKeyMapMasterUniqueKeys(t *testing.T) {
+	bound := make(map[string]string)
+	for _, group := range keysMaster.FullHelp() {
+		for _, binding := range group {
+			for _, k := range binding.Keys() {
+				if other, exists := bound[k]; exists {
+					t.Errorf("key %q is bound to both %q and %q", k, other, binding.Help().Desc)
+				}
+				bound[k] = binding.Help().Desc
+			}
+		}
+	}
+
+	for _, card := range pointOptions {
+		if desc, exists := bound[card]; exists {
+			t.Errorf("card %q collides with the %q binding", card, desc)
+		}
+	}
+}
+
 // TestKeyMapMasterShortHelp tests the short help display
 func TestKeyMapMasterShortHelp(t *testing.T) {
 	shortHelp := keysMaster.ShortHelp()
 
-	expectedCount := 7 // One, Three, Six, Reveal, Clear, Disconnect, Quit
+	expectedCount := 24 // One, Three, Six, Discuss, Reveal, Share, Reopen, Revote, Clear, Skip, Freeze, Deck, Export, Copy, Disconnect, Quit, Up, Down, RevealOne, Whisper, Announce, Feed, Tally, Help
 	if len(shortHelp) != expectedCount {
 		t.Errorf("ShortHelp() returned %d bindings, want %d", len(shortHelp), expectedCount)
 	}
@@ -115,18 +213,86 @@ func TestKeyMapMasterShortHelp(t *testing.T) {
 func TestKeyMapMasterFullHelp(t *testing.T) {
 	fullHelp := keysMaster.FullHelp()
 
-	if len(fullHelp) != 2 {
-		t.Errorf("FullHelp() returned %d groups, want 2", len(fullHelp))
+	if len(fullHelp) != 3 {
+		t.Errorf("FullHelp() returned %d groups, want 3", len(fullHelp))
+	}
+
+	// First group should have 4 timer keys
+	if len(fullHelp[0]) != 4 {
+		t.Errorf("FullHelp() first group has %d bindings, want 4", len(fullHelp[0]))
+	}
+
+	// Second group should have 12 action keys
+	if len(fullHelp[1]) != 12 {
+		t.Errorf("FullHelp() second group has %d bindings, want 12", len(fullHelp[1]))
+	}
+
+	// Third group should have 8 player, message, and view keys
+	if len(fullHelp[2]) != 8 {
+		t.Errorf("FullHelp() third group has %d bindings, want 8", len(fullHelp[2]))
+	}
+}
+
+// TestCycleHelp tests that the help key cycles through full, short, and hidden
+// help and back
+func TestCycleHelp(t *testing.T) {
+	state = newGameState()
+
+	var model tea.Model = newMasterView()
+	tests := []struct {
+		wantMode    helpMode
+		wantShowAll bool
+		wantHelp    string
+	}{
+		{helpShort, false, "reveal"},
+		{helpHidden, false, "cycle help"},
+		{helpFull, true, "discussion timer"},
+	}
+
+	for _, tt := range tests {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+		m := model.(masterView)
+		if m.helpMode != tt.wantMode || m.help.ShowAll != tt.wantShowAll {
+			t.Errorf("help mode = %d (show all %v), want %d (show all %v)", m.helpMode, m.help.ShowAll, tt.wantMode, tt.wantShowAll)
+		}
+		if view := m.View(); !strings.Contains(view, tt.wantHelp) {
+			t.Errorf("View() in help mode %d doesn't contain %q:\n%s", tt.wantMode, tt.wantHelp, view)
+		}
+	}
+
+	if view := model.View(); !strings.Contains(view, "reveal") {
+		t.Fatalf("full help is missing the reveal key")
 	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if view := model.View(); strings.Contains(view, "discussion timer") {
+		t.Errorf("hidden help still shows the key bindings:\n%s", view)
+	}
+}
+
+// TestNoTimerDisablesBindings tests that -no-timer disables the timer bindings
+func TestNoTimerDisablesBindings(t *testing.T) {
+	defer func(disabled bool) { options.NoTimer = disabled }(options.NoTimer)
 
-	// First group should have 3 timer keys
-	if len(fullHelp[0]) != 3 {
-		t.Errorf("FullHelp() first group has %d bindings, want 3", len(fullHelp[0]))
+	options.NoTimer = true
+	m := newMasterView()
+	for name, binding := range map[string]key.Binding{
+		"one": m.keys.One, "three": m.keys.Three, "six": m.keys.Six, "discuss": m.keys.Discuss,
+	} {
+		if binding.Enabled() {
+			t.Errorf("%s timer binding enabled with -no-timer", name)
+		}
+	}
+	if !m.keys.Reveal.Enabled() {
+		t.Errorf("reveal binding disabled with -no-timer")
+	}
+	if !keysMaster.One.Enabled() {
+		t.Errorf("-no-timer changed the shared key map")
 	}
 
-	// Second group should have 4 action keys
-	if len(fullHelp[1]) != 4 {
-		t.Errorf("FullHelp() second group has %d bindings, want 4", len(fullHelp[1]))
+	options.NoTimer = false
+	if m := newMasterView(); !m.keys.One.Enabled() {
+		t.Errorf("one timer binding disabled without -no-timer")
 	}
 }
 
@@ -157,3 +323,984 @@ func TestGameStateInitialization(t *testing.T) {
 		t.Fatal("state.players is nil")
 	}
 }
+
+// TestRevoteAttempt tests that a re-vote increments the attempt and a new round resets it
+func TestRevoteAttempt(t *testing.T) {
+	state.mu.Lock()
+	state.round = 1
+	state.attempt = 1
+	state.masterRevealed = true
+	setTestPlayers(map[string]*playerState{
+		"alice": {name: "alice", points: "5", selected: true},
+	})
+	state.mu.Unlock()
+
+	revote()
+
+	state.mu.RLock()
+	if state.round != 1 || state.attempt != 2 {
+		t.Errorf("after revote() round = %d.%d, want 1.2", state.round, state.attempt)
+	}
+	if state.masterRevealed {
+		t.Errorf("after revote() revealed = true, want false")
+	}
+	if player := state.players["alice"]; player.selected || player.points != "" {
+		t.Errorf("after revote() player vote = %q (selected %v), want cleared", player.points, player.selected)
+	}
+	state.mu.RUnlock()
+
+	nextRound()
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if state.round != 2 || state.attempt != 1 {
+		t.Errorf("after nextRound() round = %d.%d, want 2.1", state.round, state.attempt)
+	}
+}
+
+// TestTimerExpiry tests the reveal side effect of each timer kind on expiry
+func TestTimerExpiry(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         timerKind
+		cancel       bool
+		wantRevealed bool
+	}{
+		{"voting timer reveals", votingTimer, false, true},
+		{"discussion timer does not reveal", discussionTimer, false, false},
+		{"cancelled voting timer does not reveal", votingTimer, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state.mu.Lock()
+			state.masterRevealed = false
+			state.mu.Unlock()
+
+			m := newMasterView()
+			setTimer(tt.kind, time.Minute)
+			id := state.timerID
+			if tt.cancel {
+				cancelTimer()
+			}
+
+			m.Update(timerExpiredMsg{id: id, kind: tt.kind})
+
+			state.mu.RLock()
+			defer state.mu.RUnlock()
+			if state.masterRevealed != tt.wantRevealed {
+				t.Errorf("revealed after expiry = %v, want %v", state.masterRevealed, tt.wantRevealed)
+			}
+		})
+	}
+}
+
+// runCmd runs a command and the commands of a batch, waiting for all of them.
+func runCmd(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, c := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runCmd(c)
+		}()
+	}
+	wg.Wait()
+}
+
+// TestTimeoutAbstain tests that an expired voting timer records "?" for the
+// silent players with -timeout-abstain, told apart from a chosen "?"
+func TestTimeoutAbstain(t *testing.T) {
+	tests := []struct {
+		name      string
+		abstain   bool
+		wantCarol playerVote
+	}{
+		{"abstain", true, playerVote{points: "?", selected: true, abstained: true}},
+		{"no abstain", false, playerVote{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state = newGameState()
+			options.TimeoutAbstain, options.NoSuspense = tt.abstain, true
+			defer func() { options.TimeoutAbstain, options.NoSuspense = false, false }()
+			for _, name := range []string{"alice", "bob", "carol"} {
+				addPlayer(name, nil)
+			}
+			castVote("alice", "5")
+			castVote("bob", "?")
+
+			m := newMasterView()
+			setTimer(votingTimer, time.Minute)
+			model, _ := m.Update(timerExpiredMsg{id: state.timerID, kind: votingTimer})
+
+			state.mu.RLock()
+			alice, bob, carol := state.players["alice"].vote(), state.players["bob"].vote(), state.players["carol"].vote()
+			state.mu.RUnlock()
+			if alice.points != "5" || alice.abstained {
+				t.Errorf("alice = %q (abstained %v), want her own 5", alice.points, alice.abstained)
+			}
+			if bob.points != "?" || bob.abstained {
+				t.Errorf("bob = %q (abstained %v), want his chosen ?", bob.points, bob.abstained)
+			}
+			if carol != tt.wantCarol {
+				t.Errorf("carol = %+v, want %+v", carol, tt.wantCarol)
+			}
+
+			view := model.View()
+			if got := strings.Contains(view, "carol: ? (abstained)"); got != tt.abstain {
+				t.Errorf("master View() shows carol abstained = %v, want %v:\n%s", got, tt.abstain, view)
+			}
+			if strings.Contains(view, "bob: ? (abstained)") {
+				t.Errorf("master View() shows bob's chosen ? as abstained:\n%s", view)
+			}
+		})
+	}
+}
+
+// TestTimerExpiryRingsBell tests that an expired timer rings the bell and
+// shows the time's up message for the master and once for each player
+func TestTimerExpiryRingsBell(t *testing.T) {
+	tests := []struct {
+		name     string
+		noBell   bool
+		wantBell string
+	}{
+		{"bell", false, "\a"},
+		{"no bell", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state = newGameState()
+			options.NoBell = tt.noBell
+			defer func() { options.NoBell = false }()
+
+			var masterOut, playerOut bytes.Buffer
+			m := newMasterView()
+			m.out = &masterOut
+			setTimer(votingTimer, time.Minute)
+
+			// Let the timer expire without waiting for it
+			state.mu.Lock()
+			state.timerEnd = time.Now().Add(-time.Second)
+			state.mu.Unlock()
+
+			_, cmd := m.Update(timerExpiredMsg{id: state.timerID, kind: votingTimer})
+			runCmd(cmd)
+			if masterOut.String() != tt.wantBell {
+				t.Errorf("master output = %q, want %q", masterOut.String(), tt.wantBell)
+			}
+			if view := m.View(); !strings.Contains(view, "⏰ Time's up — reveal now") {
+				t.Errorf("master view doesn't show the time's up message:\n%s", view)
+			}
+
+			p := playerView{out: &playerOut}
+			runCmd(p.notifyTimerUp())
+			runCmd(p.notifyTimerUp())
+			if playerOut.String() != tt.wantBell {
+				t.Errorf("player output = %q, want %q once", playerOut.String(), tt.wantBell)
+			}
+		})
+	}
+}
+
+// TestRevealSuspense tests that the reveal counts down before the master view
+// shows the votes and statistics, unless the countdown is skipped
+func TestRevealSuspense(t *testing.T) {
+	tests := []struct {
+		name       string
+		noSuspense bool
+		wantFrames []string
+	}{
+		{"countdown", false, []string{"Revealing... 3", "Revealing... 3 2", "Revealing... 3 2 1"}},
+		{"no suspense", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state = newGameState()
+			setTestPlayers(map[string]*playerState{
+				"alice": {name: "alice", points: "3", selected: true},
+				"bob":   {name: "bob", points: "5", selected: true},
+			})
+			options.NoSuspense = tt.noSuspense
+			defer func() { options.NoSuspense = false }()
+
+			var model tea.Model = newMasterView()
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+
+			for _, frame := range tt.wantFrames {
+				view := model.View()
+				if !strings.Contains(view, frame) {
+					t.Errorf("View() doesn't show %q:\n%s", frame, view)
+				}
+				if strings.Contains(view, "Average:") || strings.Contains(view, "alice: 3") {
+					t.Errorf("View() shows the votes during the countdown:\n%s", view)
+				}
+				model, _ = model.Update(revealTickMsg{id: state.revealID})
+			}
+
+			view := model.View()
+			for _, want := range []string{"alice: 3", "bob: 5", "Average: 4", "Median: 4"} {
+				if !strings.Contains(view, want) {
+					t.Errorf("final View() doesn't contain %q:\n%s", want, view)
+				}
+			}
+			if strings.Contains(view, "Revealing...") {
+				t.Errorf("final View() still shows the countdown:\n%s", view)
+			}
+		})
+	}
+}
+
+// TestAutoClear tests that the round is recorded and cleared after a reveal,
+// unless a manual action cancelled the pending auto-clear
+func TestAutoClear(t *testing.T) {
+	tests := []struct {
+		name        string
+		manual      string
+		wantRound   int
+		wantAttempt int
+	}{
+		{"auto-clear", "", 2, 1},
+		{"reopen cancels", "o", 1, 1},
+		{"re-vote cancels", "v", 1, 2},
+		{"clear cancels", "c", 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state = newGameState()
+			state.votingStart = time.Now()
+			setTestPlayers(map[string]*playerState{
+				"alice": {name: "alice", points: "3", selected: true},
+			})
+			options.AutoClear = 30
+			defer func() { options.AutoClear = 0 }()
+
+			var model tea.Model = newMasterView()
+			model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+			if cmd == nil {
+				t.Fatal("reveal returned no command")
+			}
+			id := state.autoClearID
+
+			if tt.manual != "" {
+				model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.manual)})
+			}
+			model.Update(autoClearMsg{id: id})
+
+			state.mu.RLock()
+			defer state.mu.RUnlock()
+			if state.round != tt.wantRound || state.attempt != tt.wantAttempt {
+				t.Errorf("round = %d.%d, want %d.%d", state.round, state.attempt, tt.wantRound, tt.wantAttempt)
+			}
+			if len(state.history) != 1 {
+				t.Errorf("history = %d records, want the revealed round only", len(state.history))
+			}
+			if tt.manual == "" && state.masterRevealed {
+				t.Errorf("revealed after auto-clear = true, want false")
+			}
+		})
+	}
+}
+
+// TestTwoMastersShareGenerations tests that a master ignores its timer expiry
+// and auto-clear once another master has cleared the round in the meantime
+func TestTwoMastersShareGenerations(t *testing.T) {
+	state = newGameState()
+	options.TimeoutAbstain, options.NoSuspense, options.AutoClear = true, true, 30
+	defer func() { options.TimeoutAbstain, options.NoSuspense, options.AutoClear = false, false, 0 }()
+	for _, name := range []string{"alice", "bob"} {
+		addPlayer(name, nil)
+	}
+	press := func(m tea.Model, keys string) tea.Model {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(keys)})
+		return m
+	}
+
+	// A starts the voting timer and B clears while it runs
+	var a, b tea.Model = newMasterView(), newMasterView()
+	a, _ = a.Update(tea.KeyMsg{Type: tea.KeyF1})
+	timerID := state.timerID
+	if state.timerEnd.IsZero() {
+		t.Fatal("F1 didn't start the voting timer")
+	}
+	castVote("alice", "3")
+	b = press(b, "c")
+	a, _ = a.Update(timerExpiredMsg{id: timerID, kind: votingTimer})
+
+	state.mu.RLock()
+	revealed, round := state.masterRevealed, state.round
+	bob := state.players["bob"].vote()
+	state.mu.RUnlock()
+	if revealed || round != 2 {
+		t.Errorf("after the stale timer revealed = %v in round %d, want a hidden round 2", revealed, round)
+	}
+	if bob.abstained {
+		t.Errorf("bob abstained at the stale timer, want him still to vote")
+	}
+
+	// A reveals with a pending auto-clear, B clears and reveals the next round
+	castVote("alice", "3")
+	castVote("bob", "5")
+	a = press(a, "r")
+	autoClearID := state.autoClearID
+	b = press(b, "c")
+	castVote("alice", "8")
+	castVote("bob", "8")
+	press(b, "r")
+	a.Update(autoClearMsg{id: autoClearID})
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if !state.masterRevealed || state.round != 3 {
+		t.Errorf("after the stale auto-clear revealed = %v in round %d, want B's revealed round 3", state.masterRevealed, state.round)
+	}
+}
+
+// TestBlindReveal tests that a blind reveal shows the votes to the master only
+// until they are shared, while a normal reveal shows them to everyone
+func TestBlindReveal(t *testing.T) {
+	tests := []struct {
+		name            string
+		blind           bool
+		wantShared      bool
+		wantSharedAfter bool
+	}{
+		{"normal reveal", false, true, true},
+		{"blind reveal", true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state = newGameState()
+			options.BlindReveal = tt.blind
+			options.NoSuspense = true
+			defer func() { options.BlindReveal, options.NoSuspense = false, false }()
+
+			var model tea.Model = newMasterView()
+			player, _ := initPlayerView("alice", nil)
+			player, _ = player.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+
+			// Sharing before the reveal shows nothing
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+			state.mu.RLock()
+			shared := state.playersRevealed
+			state.mu.RUnlock()
+			if shared {
+				t.Errorf("playersRevealed before reveal = true, want false")
+			}
+
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+			if view := model.View(); !strings.Contains(view, "alice: 5") {
+				t.Errorf("master View() after reveal doesn't show the vote:\n%s", view)
+			}
+			view := player.View()
+			if !strings.Contains(view, "Voting closed") {
+				t.Errorf("player View() after reveal isn't closed:\n%s", view)
+			}
+			if got := strings.Contains(view, "Waiting for the Scrum Master to share"); got == tt.wantShared {
+				t.Errorf("player View() after reveal waits for sharing = %v, want %v", got, !tt.wantShared)
+			}
+
+			model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+			state.mu.RLock()
+			shared = state.playersRevealed
+			state.mu.RUnlock()
+			if shared != tt.wantSharedAfter {
+				t.Errorf("playersRevealed after R = %v, want %v", shared, tt.wantSharedAfter)
+			}
+			if view := player.View(); strings.Contains(view, "Waiting for the Scrum Master to share") {
+				t.Errorf("player View() after sharing still waits:\n%s", view)
+			}
+		})
+	}
+}
+
+// TestMinVoters tests that the votes are only revealed once enough players
+// voted, and that an expiring voting timer reveals them anyway
+func TestMinVoters(t *testing.T) {
+	state = newGameState()
+	options.MinVoters = 2
+	options.NoSuspense = true
+	defer func() { options.MinVoters, options.NoSuspense = 0, false }()
+
+	addPlayer("alice", nil)
+	addPlayer("bob", nil)
+	castVote("alice", "5")
+
+	var model tea.Model = newMasterView()
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	state.mu.RLock()
+	revealed := state.masterRevealed
+	state.mu.RUnlock()
+	if revealed {
+		t.Fatalf("revealed with 1 of 2 required votes")
+	}
+	if status := model.(masterView).status; status != "Need 1 more vote to reveal" {
+		t.Errorf("status = %q, want %q", status, "Need 1 more vote to reveal")
+	}
+	if status := missingVotersStatus(2); status != "Need 2 more votes to reveal" {
+		t.Errorf("missingVotersStatus(2) = %q, want %q", status, "Need 2 more votes to reveal")
+	}
+
+	castVote("bob", "8")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	state.mu.RLock()
+	revealed = state.masterRevealed
+	state.mu.RUnlock()
+	if !revealed {
+		t.Errorf("not revealed with 2 of 2 required votes")
+	}
+
+	// The voting timer reveals with too few votes, noting it
+	clearPlayerState()
+	castVote("alice", "5")
+	m := newMasterView()
+	setTimer(votingTimer, time.Minute)
+	model, _ = m.Update(timerExpiredMsg{id: state.timerID, kind: votingTimer})
+	state.mu.RLock()
+	revealed = state.masterRevealed
+	state.mu.RUnlock()
+	if !revealed {
+		t.Errorf("voting timer didn't reveal with too few votes")
+	}
+	if status := model.(masterView).status; !strings.Contains(status, "fewer than 2 votes") {
+		t.Errorf("status = %q, want a note about too few votes", status)
+	}
+}
+
+// TestWaitingOn tests that the master is told exactly which connected players
+// haven't voted yet
+func TestWaitingOn(t *testing.T) {
+	state = newGameState()
+	for _, name := range []string{"alice", "bob", "dave", "erin"} {
+		addPlayer(name, nil)
+	}
+	castVote("alice", "5")
+	castVote("bob", "8")
+	// Players who lost their connection aren't chased
+	addPlayer("frank", nil)
+	state.mu.Lock()
+	state.players["frank"].offline = true
+	state.mu.Unlock()
+
+	state.mu.RLock()
+	got := waitingOn()
+	state.mu.RUnlock()
+	if want := []string{"dave", "erin"}; !slices.Equal(got, want) {
+		t.Errorf("waitingOn() = %v, want %v", got, want)
+	}
+
+	// Lines are padded to the width of the view
+	view := newMasterView().View()
+	waiting := false
+	for _, line := range strings.Split(view, "\n") {
+		if strings.HasSuffix(strings.TrimSpace(line), "Waiting on: dave, erin") {
+			waiting = true
+		}
+	}
+	if !waiting {
+		t.Errorf("View() doesn't call out the players who haven't voted:\n%s", view)
+	}
+}
+
+// TestSparkline tests mapping vote counts to block characters scaled to the
+// highest count
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []int
+		want   string
+	}{
+		{"scaled to the highest", []int{1, 3, 8, 2}, "▁▃█▂"},
+		{"every level", []int{1, 2, 3, 4, 5, 6, 7, 8}, "▁▂▃▄▅▆▇█"},
+		{"zero counts are blank", []int{0, 2, 0, 1}, " █ ▄"},
+		{"single vote", []int{0, 1}, " █"},
+		{"no votes", []int{0, 0, 0}, ""},
+		{"empty deck", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.counts); got != tt.want {
+				t.Errorf("sparkline(%v) = %q, want %q", tt.counts, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRoundsPlayed tests that rounds are counted on their first reveal only
+func TestRoundsPlayed(t *testing.T) {
+	state = newGameState()
+	reveal := func() { revealVotes() }
+
+	steps := []struct {
+		name string
+		step func()
+		want int
+	}{
+		{"before reveal", func() {}, 0},
+		{"first reveal", reveal, 1},
+		{"reveal again", reveal, 1},
+		{"clear player state", clearPlayerState, 1},
+		{"reveal after reopen", reveal, 1},
+		{"re-vote", revote, 1},
+		{"reveal after re-vote", reveal, 1},
+		{"next round", nextRound, 1},
+		{"reveal next round", reveal, 2},
+	}
+
+	for _, tt := range steps {
+		tt.step()
+
+		state.mu.RLock()
+		got := state.roundsPlayed
+		state.mu.RUnlock()
+
+		if got != tt.want {
+			t.Errorf("%s: roundsPlayed = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestReadyCount tests the aggregation of ready check-ins
+func TestReadyCount(t *testing.T) {
+	state = newGameState()
+	for _, name := range []string{"alice", "bob", "carol"} {
+		addPlayer(name, nil)
+	}
+
+	toggleReady("alice")
+	toggleReady("bob")
+	toggleReady("bob")
+	toggleReady("Carol")
+
+	state.mu.RLock()
+	ready, total := readyCount()
+	state.mu.RUnlock()
+	if ready != 2 || total != 3 {
+		t.Errorf("readyCount() = %d/%d, want 2/3", ready, total)
+	}
+
+	// Players who lost their connection don't hold up the others
+	state.mu.Lock()
+	state.players["bob"].offline = true
+	state.mu.Unlock()
+	state.mu.RLock()
+	ready, total = readyCount()
+	state.mu.RUnlock()
+	if ready != 2 || total != 2 {
+		t.Errorf("readyCount() with bob offline = %d/%d, want 2/2", ready, total)
+	}
+
+	clearPlayerState()
+
+	state.mu.RLock()
+	ready, _ = readyCount()
+	state.mu.RUnlock()
+	if ready != 0 {
+		t.Errorf("readyCount() after clearPlayerState() = %d, want 0", ready)
+	}
+}
+
+// TestLiveTally tests that the pre-reveal tally counts the votes per card,
+// leaves out players who haven't voted, and is only shown to the master, who
+// only sees the sparkline before the reveal along with it
+func TestLiveTally(t *testing.T) {
+	state = newGameState()
+	for _, name := range []string{"alice", "bob", "carol", "dave"} {
+		addPlayer(name, nil)
+	}
+
+	state.mu.RLock()
+	tally := liveTally()
+	state.mu.RUnlock()
+	if tally != "" {
+		t.Errorf("liveTally() without votes = %q, want empty", tally)
+	}
+
+	castVote("alice", "8")
+	castVote("bob", "5")
+	castVote("carol", "5")
+
+	state.mu.RLock()
+	tally = liveTally()
+	state.mu.RUnlock()
+	if want := "5: 2, 8: 1"; tally != want {
+		t.Errorf("liveTally() = %q, want %q", tally, want)
+	}
+
+	state.mu.RLock()
+	spark := voteSparkline()
+	state.mu.RUnlock()
+
+	var model tea.Model = newMasterView()
+	if view := model.View(); strings.Contains(view, "Live tally") || strings.Contains(view, spark) {
+		t.Errorf("master View() shows the tally or sparkline before toggling it:\n%s", view)
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if view := model.View(); !strings.Contains(view, "📊 Live tally: 5: 2, 8: 1") || !strings.Contains(view, spark) {
+		t.Errorf("master View() doesn't show the tally and sparkline:\n%s", view)
+	}
+
+	player, _ := initPlayerView("erin", nil)
+	if view := player.View(); strings.Contains(view, "Live tally") || strings.Contains(view, "5: 2") {
+		t.Errorf("player View() leaks the tally:\n%s", view)
+	}
+}
+
+// TestFreezeJoins tests that new players can't join while joins are locked,
+// but players who lost their connection may still reconnect
+func TestFreezeJoins(t *testing.T) {
+	state = newGameState()
+	bobKey := newTestPublicKey(t)
+	addPlayer("alice", nil)
+	addPlayer("bob", &stubSession{key: bobKey})
+	state.mu.Lock()
+	state.players["bob"].offline = true
+	state.mu.Unlock()
+
+	var model tea.Model = newMasterView()
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if view := model.View(); !strings.Contains(view, "Joins are locked") {
+		t.Errorf("master View() doesn't show that joins are locked:\n%s", view)
+	}
+
+	if err := checkJoin("carol", nil); !errors.Is(err, errJoinsLocked) {
+		t.Errorf("checkJoin(carol) while frozen err = %v, want %v", err, errJoinsLocked)
+	}
+	if err := checkJoin("bob", &stubSession{key: bobKey}); err != nil {
+		t.Errorf("checkJoin(bob) reconnecting while frozen err = %v, want nil", err)
+	}
+	if joinsLocked() {
+		t.Errorf("joinsLocked() = true while bob may reconnect, want false")
+	}
+	addPlayer("bob", &stubSession{key: bobKey})
+	if !joinsLocked() {
+		t.Errorf("joinsLocked() = false with everyone online, want true")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if err := checkJoin("carol", nil); err != nil {
+		t.Errorf("checkJoin(carol) after unfreezing err = %v, want nil", err)
+	}
+	if joinsLocked() {
+		t.Errorf("joinsLocked() after unfreezing = true, want false")
+	}
+}
+
+// TestRevealSinglePlayer tests revealing only the selected player's vote
+func TestRevealSinglePlayer(t *testing.T) {
+	state = newGameState()
+	addPlayer("alice", nil)
+	addPlayer("bob", nil)
+	castVote("alice", "3")
+	castVote("bob", "8")
+
+	m := newMasterView()
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+
+	state.mu.RLock()
+	aliceRevealed := state.players["alice"].revealed
+	bobRevealed := state.players["bob"].revealed
+	globalRevealed := state.masterRevealed
+	state.mu.RUnlock()
+
+	if aliceRevealed || !bobRevealed || globalRevealed {
+		t.Errorf("revealed alice=%v bob=%v global=%v, want only bob", aliceRevealed, bobRevealed, globalRevealed)
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "bob: 8") || strings.Contains(view, "alice: 3") {
+		t.Errorf("View() should show only bob's vote\nGot: %s", view)
+	}
+
+	clearPlayerState()
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if state.players["bob"].revealed {
+		t.Errorf("bob revealed after clearPlayerState() = true, want false")
+	}
+}
+
+// TestAnnouncement tests setting and clearing the announcement from the master view
+func TestAnnouncement(t *testing.T) {
+	state = newGameState()
+
+	var model tea.Model = newMasterView()
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5 min break")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	state.mu.RLock()
+	got := state.announcement
+	state.mu.RUnlock()
+	if got != "5 min break" {
+		t.Errorf("announcement = %q, want %q", got, "5 min break")
+	}
+
+	// Late joiners see the announcement too
+	player, _ := initPlayerView("alice", nil)
+	if view := player.View(); !strings.Contains(view, "5 min break") {
+		t.Errorf("player View() missing announcement\nGot: %s", view)
+	}
+
+	// Submitting an empty announcement clears it
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if state.announcement != "" {
+		t.Errorf("announcement after clear = %q, want empty", state.announcement)
+	}
+}
+
+// TestPlayerOrder tests that the sorted player order follows joins and leaves
+func TestPlayerOrder(t *testing.T) {
+	state = newGameState()
+	for _, name := range []string{"carol", "alice", "bob", "alice"} {
+		state.putPlayer(name, &playerState{name: name})
+	}
+	state.deletePlayer("bob")
+	state.deletePlayer("dave")
+	state.putPlayer("aaron", &playerState{name: "aaron"})
+
+	want := []string{"aaron", "alice", "carol"}
+	if got := sortedPlayerKeys(); !slices.Equal(got, want) {
+		t.Errorf("sortedPlayerKeys() = %v, want %v", got, want)
+	}
+	if len(state.players) != len(want) {
+		t.Errorf("players = %d, want %d", len(state.players), len(want))
+	}
+}
+
+// benchmarkPlayers fills the game state with n players for the benchmarks.
+func benchmarkPlayers(n int) {
+	state = newGameState()
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("player%03d", i)
+		state.putPlayer(name, &playerState{name: name})
+	}
+}
+
+// BenchmarkPlayerKeysSortEachFrame measures sorting the player names on every
+// render, as the master view did before keeping them sorted on join and leave.
+func BenchmarkPlayerKeysSortEachFrame(b *testing.B) {
+	benchmarkPlayers(100)
+	b.ReportAllocs()
+	for b.Loop() {
+		keys := make([]string, 0, len(state.players))
+		for key := range state.players {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+}
+
+// BenchmarkSortedPlayerKeys measures reading the maintained player order.
+func BenchmarkSortedPlayerKeys(b *testing.B) {
+	benchmarkPlayers(100)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = sortedPlayerKeys()
+	}
+}
+
+// BenchmarkPlayerShowResults measures rendering the revealed votes in the
+// player view, which reads the maintained player order as well.
+func BenchmarkPlayerShowResults(b *testing.B) {
+	benchmarkPlayers(100)
+	state.masterRevealed = true
+	state.playersRevealed = true
+	p := playerView{name: "player000"}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = p.showResults()
+	}
+}
+
+// hungSession is an SSH session whose writes block until it's released,
+// like a half-dead connection.
+type hungSession struct {
+	stubSession
+	release chan struct{}
+}
+
+// Write blocks until the session is released.
+func (s *hungSession) Write(p []byte) (int, error) {
+	<-s.release
+	return 0, io.EOF
+}
+
+// TestQuitPlayersBrokenSessions tests that failing and hung sessions are still
+// removed without blocking the Scrum Master
+func TestQuitPlayersBrokenSessions(t *testing.T) {
+	state = newGameState()
+	failing := &stubSession{closed: true, closeErr: errors.New("connection reset")}
+	hung := &hungSession{release: make(chan struct{})}
+	defer close(hung.release)
+	healthy := &stubSession{}
+
+	state.mu.Lock()
+	state.putPlayer("alice", &playerState{name: "alice", session: failing})
+	state.putPlayer("bob", &playerState{name: "bob", session: hung})
+	state.putPlayer("carol", &playerState{name: "carol", session: healthy})
+	state.putPlayer("dave", &playerState{name: "dave"})
+
+	quitDone := make(chan struct{})
+	go func() {
+		quitPlayers()
+		close(quitDone)
+	}()
+	select {
+	case <-quitDone:
+	case <-time.After(time.Second):
+		t.Fatal("quitPlayers() blocked on a hung session")
+	}
+	if len(state.players) != 0 || len(sortedPlayerKeys()) != 0 {
+		t.Errorf("players = %d after quitPlayers(), want 0", len(state.players))
+	}
+	state.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for !healthy.isClosed() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !healthy.isClosed() {
+		t.Errorf("healthy session not closed")
+	}
+	if !strings.Contains(healthy.output(), resetTerminalSequence) {
+		t.Errorf("healthy session terminal not reset, got %q", healthy.output())
+	}
+}
+
+// TestVersionFooter tests the version and uptime footer of the master view
+func TestVersionFooter(t *testing.T) {
+	defer func(version string, start time.Time) {
+		serverVersion, serverStart = version, start
+	}(serverVersion, serverStart)
+
+	serverVersion = "v1.2.3 (abc1234)"
+	serverStart = time.Now()
+
+	tests := []struct {
+		name   string
+		uptime time.Duration
+		want   string
+	}{
+		{"just started", 30 * time.Second, "Showdown v1.2.3 (abc1234) • up 0m"},
+		{"minutes", 15*time.Minute + 20*time.Second, "Showdown v1.2.3 (abc1234) • up 15m"},
+		{"hours", 2*time.Hour + 5*time.Minute, "Showdown v1.2.3 (abc1234) • up 2h5m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := versionFooter(serverStart.Add(tt.uptime)); got != tt.want {
+				t.Errorf("versionFooter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if view := newMasterView().View(); !strings.Contains(view, "Showdown v1.2.3 (abc1234)") {
+		t.Errorf("master view missing version footer\nGot: %s", view)
+	}
+}
+
+// TestSharedMasters tests that a reveal by one Scrum Master shows in the view
+// of another, and that revealing twice at once counts once
+func TestSharedMasters(t *testing.T) {
+	state = newGameState()
+	options.NoSuspense = true
+	defer func() { options.NoSuspense = false }()
+	addPlayer("alice", nil)
+	castVote("alice", "5")
+
+	robin := newMasterView()
+	sam := newMasterView()
+	robin.Update(keyRunes("r"))
+	if view := sam.View(); !strings.Contains(view, "alice: 5") {
+		t.Errorf("second master doesn't see the reveal:\n%s", view)
+	}
+
+	state.mu.Lock()
+	state.masterRevealed = false
+	state.mu.Unlock()
+	var wg sync.WaitGroup
+	var reveals atomic.Int32
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if revealVotes() {
+				reveals.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if reveals.Load() != 1 {
+		t.Errorf("concurrent reveals = %d, want 1", reveals.Load())
+	}
+}
+
+// TestCapacityBadge tests that the master sees how full the room is against
+// the configured player limit, and nothing without one
+func TestCapacityBadge(t *testing.T) {
+	tests := []struct {
+		name    string
+		players int
+		limit   int
+		want    string
+		filled  int
+	}{
+		{"no limit", 8, 0, "", 0},
+		{"empty room", 0, 20, "Players: 0/20", 0},
+		{"partly full", 8, 20, "Players: 8/20", 4},
+		{"one player rounds up", 1, 20, "Players: 1/20", 1},
+		{"full", 20, 20, "Players: 20/20", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := capacityBadge(tt.players, tt.limit)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("capacityBadge() = %q, want nothing", got)
+				}
+				return
+			}
+			if !strings.HasPrefix(got, tt.want+" ") {
+				t.Errorf("capacityBadge() = %q, want prefix %q", got, tt.want)
+			}
+			if filled := strings.Count(got, "█"); filled != tt.filled {
+				t.Errorf("capacityBadge() filled = %d, want %d", filled, tt.filled)
+			}
+		})
+	}
+
+	state = newGameState()
+	options.MaxPlayers = 2
+	defer func() { options.MaxPlayers = 0 }()
+	addPlayer("alice", nil)
+	addPlayer("bob", nil)
+	if view := newMasterView().View(); !strings.Contains(view, "Players: 2/2") {
+		t.Errorf("master View() lacks the capacity badge:\n%s", view)
+	}
+	if err := checkJoin("carol", nil); err == nil {
+		t.Errorf("join beyond the player limit allowed")
+	}
+}
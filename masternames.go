@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// defaultMasterName is shown for a Scrum Master without a mapped name or SSH
+// username.
+const defaultMasterName = "Scrum Master"
+
+// masterNames maps SSH key fingerprints to the names of the people using them
+// as Scrum Master. It is loaded from the -master-names file at startup.
+var masterNames = map[string]string{}
+
+// parseMasterNames reads a name mapping with one entry per line: a SHA256 key
+// fingerprint as printed by ssh-keygen -l, followed by the name, which may
+// contain spaces. Empty lines and lines starting with # are ignored.
+func parseMasterNames(r io.Reader) (map[string]string, error) {
+	names := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fingerprint, name, _ := strings.Cut(line, " ")
+		name = strings.TrimSpace(name)
+		if !strings.HasPrefix(fingerprint, "SHA256:") {
+			return nil, fmt.Errorf("line %d: expected a key fingerprint, got %q", lineNo, fingerprint)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("line %d: missing name for %s", lineNo, fingerprint)
+		}
+		names[fingerprint] = name
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// loadMasterNames replaces the name mapping with the entries of the file at
+// path.
+func loadMasterNames(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open master names: %w", err)
+	}
+	defer f.Close()
+
+	names, err := parseMasterNames(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse master names %s: %w", path, err)
+	}
+	masterNames = names
+
+	return nil
+}
+
+// masterDisplayName returns the name of the person behind a Scrum Master
+// connection: the mapped name of their key, else their SSH username, else
+// defaultMasterName.
+func masterDisplayName(key gossh.PublicKey, user string) string {
+	if key != nil {
+		if name, exists := masterNames[gossh.FingerprintSHA256(key)]; exists {
+			return name
+		}
+	}
+	if user != "" {
+		return user
+	}
+	return defaultMasterName
+}
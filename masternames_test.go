@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// TestMasterDisplayName tests the lookup of the master's name by key fingerprint
+func TestMasterDisplayName(t *testing.T) {
+	defer func(names map[string]string) { masterNames = names }(masterNames)
+
+	known := newTestPublicKey(t)
+	path := filepath.Join(t.TempDir(), "masters.txt")
+	content := "# Scrum Masters\n" + gossh.FingerprintSHA256(known) + " Robin de Vries\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadMasterNames(path); err != nil {
+		t.Fatalf("loadMasterNames() err = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		key  gossh.PublicKey
+		user string
+		want string
+	}{
+		{"known fingerprint", known, "robin", "Robin de Vries"},
+		{"unknown key falls back to user", newTestPublicKey(t), "alice", "alice"},
+		{"no key falls back to user", nil, "bob", "bob"},
+		{"no key and user", nil, "", defaultMasterName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := masterDisplayName(tt.key, tt.user); got != tt.want {
+				t.Errorf("masterDisplayName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseMasterNamesErrors tests that malformed mapping lines are rejected
+func TestParseMasterNamesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"missing name", "SHA256:abc\n"},
+		{"not a fingerprint", "robin Robin\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseMasterNames(strings.NewReader(tt.content)); err == nil {
+				t.Errorf("parseMasterNames(%q) err = nil, want error", tt.content)
+			}
+		})
+	}
+}
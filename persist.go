@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// snapshotInterval is how often the game state is checked for changes and
+// written to the state file.
+const snapshotInterval = time.Second
+
+// playerSnapshot is the persisted form of a player. Sessions can't be
+// restored, so only the name and vote with its notes are kept.
+type playerSnapshot struct {
+	Name         string     `json:"name"`
+	Fingerprint  string     `json:"fingerprint,omitempty"`
+	Points       string     `json:"points,omitempty"`
+	Selected     bool       `json:"selected"`
+	Risk         string     `json:"risk,omitempty"`
+	RiskSelected bool       `json:"risk_selected,omitempty"`
+	Confidence   confidence `json:"confidence,omitempty"`
+	Abstained    bool       `json:"abstained,omitempty"`
+	Commit       string     `json:"commit,omitempty"`
+	VotedAt      time.Time  `json:"voted_at,omitzero"`
+	ChangedAt    time.Time  `json:"changed_at,omitzero"`
+	VoteChanges  int        `json:"vote_changes,omitempty"`
+}
+
+// storySnapshot is the persisted form of a backlog story.
+type storySnapshot struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Skipped bool   `json:"skipped,omitempty"`
+}
+
+// roundSnapshot is the persisted form of a round history entry.
+type roundSnapshot struct {
+	Round   int       `json:"round"`
+	Attempt int       `json:"attempt"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Story   string    `json:"story,omitempty"`
+	Skipped bool      `json:"skipped,omitempty"`
+}
+
+// gameSnapshot is the persisted form of the game state, written to the state
+// file so a restarted server resumes the meeting with its backlog position and
+// the round history of the session summary.
+type gameSnapshot struct {
+	Round           int              `json:"round"`
+	Attempt         int              `json:"attempt"`
+	RoundsPlayed    int              `json:"rounds_played"`
+	Revealed        bool             `json:"revealed"`
+	PlayersRevealed bool             `json:"players_revealed"`
+	VotingStart     time.Time        `json:"voting_start,omitzero"`
+	Players         []playerSnapshot `json:"players"`
+	Backlog         []storySnapshot  `json:"backlog,omitempty"`
+	StoryIndex      int              `json:"story_index"`
+	History         []roundSnapshot  `json:"history,omitempty"`
+	Joined          []string         `json:"joined,omitempty"`
+}
+
+// marshalSnapshot serializes the current game state under the read lock.
+func marshalSnapshot() ([]byte, error) {
+	state.mu.RLock()
+	snapshot := gameSnapshot{
+		Round:           state.round,
+		Attempt:         state.attempt,
+		RoundsPlayed:    state.roundsPlayed,
+		Revealed:        state.masterRevealed,
+		PlayersRevealed: state.playersRevealed,
+		Players:         make([]playerSnapshot, 0, len(state.players)),
+		StoryIndex:      state.storyIndex,
+	}
+	state.votingStartMu.Lock()
+	snapshot.VotingStart = state.votingStart
+	state.votingStartMu.Unlock()
+	for _, key := range sortedPlayerKeys() {
+		player := state.players[key]
+		player.mu.Lock()
+		snapshot.Players = append(snapshot.Players, playerSnapshot{
+			Name:         player.name,
+			Fingerprint:  player.fingerprint,
+			Points:       player.points,
+			Selected:     player.selected,
+			Risk:         player.risk,
+			RiskSelected: player.riskSelected,
+			Confidence:   player.confidence,
+			Abstained:    player.abstained,
+			Commit:       player.commit,
+			VotedAt:      player.votedAt,
+			ChangedAt:    player.changedAt,
+			VoteChanges:  player.voteChanges,
+		})
+		player.mu.Unlock()
+	}
+	for _, story := range state.backlog {
+		snapshot.Backlog = append(snapshot.Backlog, storySnapshot{ID: story.ID, Title: story.Title, Skipped: story.skipped})
+	}
+	for _, r := range state.history {
+		snapshot.History = append(snapshot.History, roundSnapshot{
+			Round:   r.round,
+			Attempt: r.attempt,
+			Start:   r.start,
+			End:     r.end,
+			Story:   r.story,
+			Skipped: r.skipped,
+		})
+	}
+	for key := range state.joined {
+		snapshot.Joined = append(snapshot.Joined, key)
+	}
+	slices.Sort(snapshot.Joined)
+	state.mu.RUnlock()
+
+	return json.MarshalIndent(snapshot, "", "  ")
+}
+
+// restoreSnapshot replaces the game state with a serialized snapshot. Restored
+// players are marked offline until they reconnect with the same name and key.
+func restoreSnapshot(data []byte) error {
+	var snapshot gameSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse state snapshot: %w", err)
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.round = max(snapshot.Round, 1)
+	state.attempt = max(snapshot.Attempt, 1)
+	state.roundsPlayed = snapshot.RoundsPlayed
+	state.masterRevealed = snapshot.Revealed
+	// Only a blind reveal keeps the votes from the players
+	state.playersRevealed = snapshot.Revealed && (snapshot.PlayersRevealed || !options.BlindReveal)
+	if state.masterRevealed {
+		state.lastPlayedRound = state.round
+	}
+	state.votingStartMu.Lock()
+	state.votingStart = snapshot.VotingStart
+	state.votingStartMu.Unlock()
+	state.resetPlayers()
+	for _, p := range snapshot.Players {
+		state.putPlayer(playerKey(p.Name), &playerState{
+			name:         p.Name,
+			fingerprint:  p.Fingerprint,
+			color:        playerColor(p.Name),
+			points:       p.Points,
+			selected:     p.Selected,
+			risk:         p.Risk,
+			riskSelected: p.RiskSelected,
+			confidence:   p.Confidence,
+			abstained:    p.Abstained,
+			commit:       p.Commit,
+			votedAt:      p.VotedAt,
+			changedAt:    p.ChangedAt,
+			voteChanges:  p.VoteChanges,
+			offline:      true,
+		})
+	}
+	// Resume the saved backlog at its story, rather than a freshly imported one
+	if snapshot.Backlog != nil {
+		state.backlog = nil
+		for _, story := range snapshot.Backlog {
+			state.backlog = append(state.backlog, Story{ID: story.ID, Title: story.Title, skipped: story.Skipped})
+		}
+		state.storyIndex = snapshot.StoryIndex
+	}
+	state.history = nil
+	for _, r := range snapshot.History {
+		state.history = append(state.history, roundRecord{
+			round:   r.Round,
+			attempt: r.Attempt,
+			start:   r.Start,
+			end:     r.End,
+			story:   r.Story,
+			skipped: r.Skipped,
+		})
+	}
+	state.joined = make(map[string]bool, len(snapshot.Joined))
+	for _, key := range snapshot.Joined {
+		state.joined[key] = true
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so a crash never leaves a truncated state file behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// loadStateFile restores the game state from path. A missing file is not an
+// error, as there is nothing to resume on a first start.
+func loadStateFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+	return restoreSnapshot(data)
+}
+
+// saveStateFile writes the current game state to path.
+func saveStateFile(path string) error {
+	data, err := marshalSnapshot()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// persistState periodically writes the game state to path whenever it has
+// changed, until done is closed.
+func persistState(path string, done <-chan struct{}) {
+	ticker := time.NewTicker(snapshotInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			data, err := marshalSnapshot()
+			if err != nil {
+				log.Error("failed to serialize state", "error", err)
+				continue
+			}
+			if bytes.Equal(data, last) {
+				continue
+			}
+			if err := writeFileAtomic(path, data); err != nil {
+				log.Error("failed to write state file", "error", err, "path", path)
+				continue
+			}
+			last = data
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSnapshotRoundTrip tests that the game state survives serialization
+func TestSnapshotRoundTrip(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	state.mu.Lock()
+	state.round = 3
+	state.attempt = 2
+	state.roundsPlayed = 2
+	state.masterRevealed = true
+	setTestPlayers(map[string]*playerState{
+		"alice": {name: "Alice", points: "5", selected: true, risk: "3", riskSelected: true, confidence: confidenceLow, votedAt: start, changedAt: start.Add(time.Second), voteChanges: 1},
+		"bob":   {name: "bob", points: "?", selected: true, abstained: true},
+	})
+	state.backlog = []Story{{ID: "SD-1", Title: "Login", skipped: true}, {ID: "SD-2", Title: "Logout"}}
+	state.storyIndex = 1
+	state.history = []roundRecord{
+		{round: 1, attempt: 1, start: start, end: start, story: "SD-1", skipped: true},
+		{round: 2, attempt: 1, start: start, end: start.Add(time.Minute)},
+	}
+	state.joined = map[string]bool{"alice": true, "bob": true, "carol": true}
+	state.mu.Unlock()
+
+	data, err := marshalSnapshot()
+	if err != nil {
+		t.Fatalf("marshalSnapshot() err = %v", err)
+	}
+
+	state = newGameState()
+	if err := restoreSnapshot(data); err != nil {
+		t.Fatalf("restoreSnapshot() err = %v", err)
+	}
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	if state.round != 3 || state.attempt != 2 || !state.masterRevealed {
+		t.Errorf("restored round = %d.%d (revealed %v), want 3.2 (revealed true)", state.round, state.attempt, state.masterRevealed)
+	}
+	if len(state.players) != 2 {
+		t.Fatalf("restored %d players, want 2", len(state.players))
+	}
+
+	alice := state.players[playerKey("Alice")]
+	if alice == nil || alice.name != "Alice" || alice.points != "5" || !alice.selected {
+		t.Errorf("restored alice = %+v, want Alice voted 5", alice)
+	}
+	if !alice.offline || alice.session != nil {
+		t.Errorf("restored alice offline = %v, want true without session", alice.offline)
+	}
+
+	if alice.risk != "3" || !alice.riskSelected || alice.confidence != confidenceLow || alice.voteChanges != 1 ||
+		!alice.votedAt.Equal(start) || !alice.changedAt.Equal(start.Add(time.Second)) {
+		t.Errorf("restored alice notes = %+v, want risk 3, low confidence, and one change", alice)
+	}
+
+	bob := state.players["bob"]
+	if bob == nil || !bob.abstained || bob.points != "?" {
+		t.Errorf("restored bob = %+v, want bob abstained", bob)
+	}
+
+	if story, ok := currentStory(); !ok || story.ID != "SD-2" || !state.backlog[0].skipped {
+		t.Errorf("restored story = %+v (backlog %+v), want SD-2 after skipped SD-1", story, state.backlog)
+	}
+	if len(state.history) != 2 || !state.history[0].skipped || state.history[1].duration() != time.Minute {
+		t.Errorf("restored history = %+v, want a skip and a round of a minute", state.history)
+	}
+	if state.roundsPlayed != 2 || len(state.joined) != 3 {
+		t.Errorf("restored rounds played = %d and joined = %v, want 2 and 3 players", state.roundsPlayed, state.joined)
+	}
+}
+
+// TestStateFileRoundTrip tests saving and loading the state file
+func TestStateFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// A missing state file is not an error
+	if err := loadStateFile(path); err != nil {
+		t.Fatalf("loadStateFile() on missing file err = %v", err)
+	}
+
+	state = newGameState()
+	addPlayer("carol", nil)
+	castVote("carol", "8")
+
+	if err := saveStateFile(path); err != nil {
+		t.Fatalf("saveStateFile() err = %v", err)
+	}
+
+	state = newGameState()
+	if err := loadStateFile(path); err != nil {
+		t.Fatalf("loadStateFile() err = %v", err)
+	}
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if carol := state.players["carol"]; carol == nil || carol.points != "8" {
+		t.Errorf("loaded carol = %+v, want vote 8", carol)
+	}
+}
+
+// TestRestoredPlayerRejoins tests that an offline player can reconnect with their vote
+func TestRestoredPlayerRejoins(t *testing.T) {
+	state = newGameState()
+	key := newTestPublicKey(t)
+	snapshot := fmt.Sprintf(`{"players":[{"name":"dave","fingerprint":%q,"points":"3","selected":true}]}`, keyFingerprint(key))
+	if err := restoreSnapshot([]byte(snapshot)); err != nil {
+		t.Fatalf("restoreSnapshot() err = %v", err)
+	}
+
+	if err := checkJoin("Dave", &stubSession{key: key}); err != nil {
+		t.Fatalf("checkJoin() for offline player err = %v, want nil", err)
+	}
+	addPlayer("Dave", &stubSession{key: key})
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	dave := state.players["dave"]
+	if dave.offline || dave.points != "3" || !dave.selected {
+		t.Errorf("rejoined dave = %+v, want online with vote 3", dave)
+	}
+}
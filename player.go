@@ -1,15 +1,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"sort"
+	"io"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
 )
 
@@ -17,27 +21,222 @@ import (
 // during voting, following a modified Fibonacci sequence plus a "?" for uncertainty.
 var pointOptions = []string{"0.5", "1", "2", "3", "5", "8", "10", "?"}
 
+const (
+	// listWidth and listHeight are the size of the card list until the
+	// terminal reports its size.
+	listWidth  = 20
+	listHeight = 20
+
+	// listChrome is how many lines of the player view aren't the card list:
+	// the padding, title, selection, roster, and footer. minListHeight keeps a
+	// few cards visible on small terminals.
+	listChrome    = 12
+	minListHeight = 5
+)
+
+// reactionOptions maps the keys players can press to the emoji reaction shown
+// next to their name in the Scrum Master view. While voting is open "?" votes
+// unknown or toggles the help, so it only reacts in reaction mode, once the
+// votes are revealed, where the help is toggled with "h".
+var reactionOptions = map[string]string{
+	"+": "👍",
+	"-": "👎",
+	"?": "🤔",
+}
+
+// reactionFor returns the reaction of the key k, leaving out "?" unless the
+// votes are revealed.
+func reactionFor(k string, revealed bool) (string, bool) {
+	if k == "?" && !revealed {
+		return "", false
+	}
+	reaction, ok := reactionOptions[k]
+	return reaction, ok
+}
+
+// reactionKeys returns the reaction keys for the footer, like "+/-/?".
+func reactionKeys(revealed bool) string {
+	if revealed {
+		return "+/-/?"
+	}
+	return "+/-"
+}
+
+// cardKeys maps a key to each card of the deck so players can vote with a
+// single key press. Single character cards use themselves. Longer cards use
+// their first character not taken by another card, skipping a leading zero,
+// so "0.5" is voted with "." and "10" with "0".
+func cardKeys(deck []string) map[string]string {
+	keys := make(map[string]string, len(deck))
+	for _, card := range deck {
+		if len([]rune(card)) == 1 {
+			keys[card] = card
+		}
+	}
+	for _, card := range deck {
+		if len([]rune(card)) == 1 {
+			continue
+		}
+		for _, r := range strings.TrimPrefix(card, "0") {
+			if _, taken := keys[string(r)]; !taken {
+				keys[string(r)] = card
+				break
+			}
+		}
+	}
+	return keys
+}
+
+// keyMapPlayer defines the key bindings available to players, including list
+// navigation, choosing and retracting a vote, ready check-ins, confidence,
+// chat, the help overlay, and quit. Quick votes with a card's key and
+// reactions depend on the deck and are handled separately.
+type keyMapPlayer struct {
+	Up         key.Binding
+	Down       key.Binding
+	Choose     key.Binding
+	Retract    key.Binding
+	Ready      key.Binding
+	Confidence key.Binding
+	Chat       key.Binding
+	Help       key.Binding
+	Quit       key.Binding
+
+	// Yes and No answer the quit confirmation and aren't part of the help.
+	Yes key.Binding
+	No  key.Binding
+}
+
+var keysPlayer = keyMapPlayer{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "previous card"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "next card"),
+	),
+	Choose: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "choose"),
+	),
+	Retract: key.NewBinding(
+		key.WithKeys("backspace", "delete"),
+		key.WithHelp("⌫", "retract vote"),
+	),
+	Ready: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "toggle ready"),
+	),
+	Confidence: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "set confidence"),
+	),
+	Chat: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "chat"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?", "h"),
+		key.WithHelp("?", "toggle help"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "esc", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	Yes: key.NewBinding(
+		key.WithKeys("y", "Y"),
+		key.WithHelp("y", "yes"),
+	),
+	No: key.NewBinding(
+		key.WithKeys("n", "N", "esc"),
+		key.WithHelp("n", "no"),
+	),
+}
+
+// newKeyMapPlayer returns the player key bindings for a deck with the given
+// card keys. When "?" votes for a card the help is toggled with "h" only.
+func newKeyMapPlayer(cards map[string]string) keyMapPlayer {
+	k := keysPlayer
+	if _, taken := cards["?"]; taken {
+		k = k.reactionMode()
+	}
+	return k
+}
+
+// reactionMode returns the bindings with the help toggled with "h" only, as
+// "?" reacts once the votes are revealed.
+func (k keyMapPlayer) reactionMode() keyMapPlayer {
+	k.Help = key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "toggle help"),
+	)
+	return k
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k keyMapPlayer) ShortHelp() []key.Binding {
+	return []key.Binding{k.Choose, k.Retract, k.Ready, k.Confidence, k.Chat, k.Help, k.Quit}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k keyMapPlayer) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Choose, k.Retract},
+		{k.Ready, k.Confidence, k.Chat},
+		{k.Help, k.Quit},
+	}
+}
+
 // PointItem represents a selectable story point value in the player's list.
 // It implements the list.Item interface for use with Bubble Tea's list component.
+// Voted marks the card the player voted for.
 type PointItem struct {
-	value string
+	value       string
+	description string
+	voted       bool
 }
 
 // FilterValue returns the value used for filtering in the list (implements list.Item).
 func (i PointItem) FilterValue() string { return i.value }
 
-// Title returns the display title for this point item (implements list.Item).
-func (i PointItem) Title() string { return i.value }
+// Title returns the display title for this point item, with a checkmark when
+// it's the player's vote (implements list.Item).
+func (i PointItem) Title() string {
+	if i.voted {
+		return i.value + " ✓"
+	}
+	return i.value
+}
 
-// Description returns an empty string as point items have no description (implements list.Item).
-func (i PointItem) Description() string { return "" }
+// Description returns the description of the card from the deck file, or an
+// empty string without one (implements list.Item).
+func (i PointItem) Description() string { return i.description }
 
 // playerView is the Bubble Tea model for the player voting interface, displaying
-// the point selection list and the player's current selection status.
+// the point selection list, the player's current selection status, the keys
+// for quick votes, the latest whisper from the Scrum Master, and the chat input.
 type playerView struct {
-	name     string
-	list     list.Model
-	selected string
+	name         string
+	list         list.Model
+	selected     string
+	cardKeys     map[string]string
+	whispers     <-chan string
+	whisper      string
+	whisperUntil time.Time
+	chat         textinput.Model
+	chatting     bool
+	out          io.Writer
+	belledTimer  time.Time
+	keys         keyMapPlayer
+	help         help.Model
+	showHelp     bool
+	confirmQuit  bool
+	deckVersion  int
+	risk         string
+	lastActive   time.Time
 }
 
 // nameInputView is the Bubble Tea model for the initial name entry screen
@@ -54,14 +253,11 @@ type delegateKeyMap struct {
 	choose key.Binding
 }
 
-// newDelegateKeyMap creates a new key map for the list delegate with
-// the enter key bound to the choose action.
+// newDelegateKeyMap creates a new key map for the list delegate with the
+// player's choose binding.
 func newDelegateKeyMap() *delegateKeyMap {
 	return &delegateKeyMap{
-		choose: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "choose"),
-		),
+		choose: keysPlayer.Choose,
 	}
 }
 
@@ -86,23 +282,101 @@ func (d delegateKeyMap) FullHelp() [][]key.Binding {
 // Init initializes the player view and starts the periodic tick command
 // for UI updates. Implements the tea.Model interface.
 func (p playerView) Init() tea.Cmd {
-	return tickEvery()
+	return tea.Batch(tickEvery(), waitForWhisper(p.whispers))
 }
 
 // Update handles incoming messages for the player view including keyboard
-// navigation, point selection with enter, quit commands, and tick updates.
-// Selection is disabled once votes are revealed. Implements the tea.Model interface.
+// navigation, point selection with enter or a card's key, retracting the
+// vote, ready check-ins, reactions, chat, the help overlay, quit commands,
+// whispers, terminal resizes, and tick updates.
+// Voting is closed once votes are revealed until the Scrum Master reopens it or
+// starts a new round. Implements the tea.Model interface.
 func (p playerView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var (
 		selectedValue string
 		cmd           tea.Cmd
 	)
 
+	// Any key counts as activity, and only dismisses a pending idle warning
+	if _, ok := msg.(tea.KeyMsg); ok {
+		now := time.Now()
+		warned := p.idleWarning(now)
+		p.lastActive = now
+		if warned {
+			return p, nil
+		}
+	}
+
+	// While chatting all keys go to the chat input
+	if msg, ok := msg.(tea.KeyMsg); ok && p.chatting {
+		return p.updateChat(msg)
+	}
+
+	// While asking to confirm quitting only the answer counts
+	if msg, ok := msg.(tea.KeyMsg); ok && p.confirmQuit {
+		switch {
+		case key.Matches(msg, p.keys.Yes):
+			p.confirmQuit = false
+			return p, p.quit()
+		case key.Matches(msg, p.keys.No):
+			p.confirmQuit = false
+		}
+		return p, nil
+	}
+
+	// Chat and reaction keys are handled before the list so "?" doesn't toggle its help
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		state.mu.RLock()
+		reacting := state.masterRevealed
+		state.mu.RUnlock()
+		if reaction, ok := reactionFor(msg.String(), reacting); ok {
+			state.mu.RLock()
+			if player, exists := state.players[playerKey(p.name)]; exists {
+				player.mu.Lock()
+				player.reaction = reaction
+				player.mu.Unlock()
+			}
+			state.mu.RUnlock()
+			return p, nil
+		}
+		if key.Matches(msg, p.keys.Confidence) {
+			cycleConfidence(p.name)
+			return p, nil
+		}
+		if key.Matches(msg, p.keys.Chat) {
+			p.chatting = true
+			p.chat.SetValue("")
+			return p, p.chat.Focus()
+		}
+		if key.Matches(msg, p.keys.Help) {
+			p.showHelp = !p.showHelp
+			return p, nil
+		}
+		if key.Matches(msg, p.keys.Retract) {
+			if retractVote(p.name) {
+				p.markVote("")
+			}
+			return p, nil
+		}
+		// Quick-vote with the card's key, unless the list uses keys to filter
+		if card, ok := p.cardKeys[msg.String()]; ok && !p.list.FilteringEnabled() {
+			p.choose(card)
+			return p, nil
+		}
+	}
+
 	// Only update list if scores aren't revealed
 	state.mu.RLock()
-	revealed := state.revealed
+	revealed := state.masterRevealed
+	player, exists := state.players[playerKey(p.name)]
+	voted := exists && player.vote().selected
 	state.mu.RUnlock()
 
+	// Forget the local selection once the round is cleared or re-voted
+	if !voted && p.selected != "" {
+		p.markVote("")
+	}
+
 	if !revealed {
 		p.list, cmd = p.list.Update(msg)
 	}
@@ -113,31 +387,184 @@ func (p playerView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "esc", "ctrl+c":
-			state.mu.Lock()
-			delete(state.players, p.name)
-			state.mu.Unlock()
-			return p, tea.Quit
-		case "enter":
-			// Only allow selection if scores aren't revealed
-			if !revealed {
-				state.mu.Lock()
-				if player, exists := state.players[p.name]; exists {
-					player.points = selectedValue
-					player.selected = true
-				}
-				state.mu.Unlock()
-				p.selected = selectedValue
+		switch {
+		case key.Matches(msg, p.keys.Quit):
+			// Players who voted are asked first, as quitting loses their vote
+			if voted {
+				p.confirmQuit = true
+				return p, nil
 			}
+			return p, p.quit()
+		case key.Matches(msg, p.keys.Ready):
+			toggleReady(p.name)
+		case key.Matches(msg, p.keys.Choose):
+			p.choose(selectedValue)
 		}
+	case tea.WindowSizeMsg:
+		// Fit the card list into the terminal inside the view's padding
+		p.list.SetSize(max(msg.Width-2, listWidth), max(msg.Height-listChrome, minListHeight))
+		p.help.Width = msg.Width
+	case whisperMsg:
+		p.whisper = string(msg)
+		p.whisperUntil = time.Now().Add(whisperDuration)
+		return p, waitForWhisper(p.whispers)
 	case tickMsg:
-		return p, tickEvery()
+		if p.idleExpired(time.Now()) {
+			log.Info("Player disconnected for inactivity", "player", p.name)
+			return p, tea.Quit
+		}
+		p.syncDeck()
+		return p, tea.Batch(tickEvery(), p.notifyTimerUp(), revealRefresh())
+	case revealTickMsg:
+		return p, nil
 	}
 
 	return p, cmd
 }
 
+// idleWarning reports whether the player is about to be disconnected for
+// inactivity, idleWarningBefore ahead of the -idle-timeout.
+func (p playerView) idleWarning(now time.Time) bool {
+	return options.IdleTimeout > 0 && now.Sub(p.lastActive) >= options.IdleTimeout-idleWarningBefore
+}
+
+// idleExpired reports whether the player has been inactive for the
+// -idle-timeout and is disconnected. Their vote is kept for when they
+// reconnect.
+func (p playerView) idleExpired(now time.Time) bool {
+	return options.IdleTimeout > 0 && now.Sub(p.lastActive) >= options.IdleTimeout
+}
+
+// quit removes the player from the game and returns the command ending their
+// session.
+func (p playerView) quit() tea.Cmd {
+	state.mu.Lock()
+	removePlayer(playerKey(p.name))
+	state.mu.Unlock()
+	return tea.Quit
+}
+
+// syncDeck rebuilds the card list and card keys once the Scrum Master has
+// switched decks. Votes were cleared with the switch, so is the selection.
+func (p *playerView) syncDeck() {
+	state.mu.RLock()
+	version := state.deckVersion
+	deck := activeDeck()
+	state.mu.RUnlock()
+
+	if version == p.deckVersion {
+		return
+	}
+	p.deckVersion = version
+	p.selected = ""
+	p.list.SetItems(pointItems(deck))
+	p.list.Select(0)
+	p.cardKeys = cardKeys(deck)
+	p.keys = newKeyMapPlayer(p.cardKeys)
+}
+
+// pointItems returns the list items of the cards of a deck, with their
+// descriptions from the deck file.
+func pointItems(deck []string) []list.Item {
+	items := make([]list.Item, len(deck))
+	for i, card := range deck {
+		items[i] = PointItem{value: card, description: cardDescriptions[card]}
+	}
+	return items
+}
+
+// notifyTimerUp rings the bell once for each expired timer, so a player away
+// from the screen notices that time's up.
+func (p *playerView) notifyTimerUp() tea.Cmd {
+	state.mu.RLock()
+	end := expiredTimer()
+	state.mu.RUnlock()
+
+	if end.IsZero() || end.Equal(p.belledTimer) {
+		return nil
+	}
+	p.belledTimer = end
+	return ringBell(p.out)
+}
+
+// choose votes the card for the effort, or for the risk once the effort is
+// voted when estimating risk. Nothing changes once votes are revealed.
+func (p *playerView) choose(card string) {
+	if p.ratingRisk() {
+		if castRisk(p.name, card) {
+			p.risk = card
+			p.markCard(card)
+		}
+		return
+	}
+	if castVote(p.name, card) {
+		p.markVote(card)
+	}
+}
+
+// ratingRisk reports whether the player's list is for the risk card, which
+// follows the effort card when estimating risk.
+func (p *playerView) ratingRisk() bool {
+	return options.Risk && p.selected != ""
+}
+
+// markVote remembers the card the player voted for as effort and marks it in
+// the list. When estimating risk, the list then moves on to the risk card. An
+// empty card clears the vote, including the risk.
+func (p *playerView) markVote(card string) {
+	p.selected = card
+	if card == "" {
+		p.risk = ""
+	}
+	if p.ratingRisk() {
+		p.list.Title = t("player.riskList")
+		p.markCard(p.risk)
+		return
+	}
+	p.list.Title = t("player.list")
+	p.markCard(card)
+}
+
+// markCard marks the card with a checkmark in the list and moves the list
+// selection to it. An empty card clears the checkmark.
+func (p *playerView) markCard(card string) {
+	for i, item := range p.list.Items() {
+		point, ok := item.(PointItem)
+		if !ok {
+			continue
+		}
+		if voted := point.value == card; point.voted != voted {
+			point.voted = voted
+			p.list.SetItem(i, point)
+		}
+		if point.value == card {
+			p.list.Select(i)
+		}
+	}
+}
+
+// updateChat handles keys while the player types a chat message: enter sends
+// it, esc closes the chat input, and any other key edits the text.
+func (p playerView) updateChat(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		p.chatting = false
+		p.chat.Blur()
+		return p, nil
+	case tea.KeyEnter:
+		if text := strings.TrimSpace(p.chat.Value()); text != "" {
+			addChatMessage(p.name, text)
+		}
+		p.chatting = false
+		p.chat.Blur()
+		return p, nil
+	}
+
+	var cmd tea.Cmd
+	p.chat, cmd = p.chat.Update(msg)
+	return p, cmd
+}
+
 // showResults renders the voting results panel displaying all player votes
 // and statistics after the Scrum Master reveals the votes.
 func (p playerView) showResults() string {
@@ -145,33 +572,43 @@ func (p playerView) showResults() string {
 	defer state.mu.RUnlock()
 
 	var s strings.Builder
-	s.WriteString("📊 Voting Results:\n\n")
+	s.WriteString(t("player.results") + "\n\n")
 
 	// Show all players and their votes
-	names := make([]string, 0, len(state.players))
-	for name := range state.players {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-
-	s.WriteString("Player Votes:\n")
+	s.WriteString(t("player.votes") + "\n")
 	var points []string
 	voted := 0
 
-	for _, name := range names {
-		player := state.players[name]
-		if player.selected {
-			fmt.Fprintf(&s, "• %s: %s\n", name, player.points)
-			points = append(points, player.points)
+	for _, key := range sortedPlayerKeys() {
+		player := state.players[key]
+		if vote := player.vote(); vote.abstained {
+			fmt.Fprintf(&s, "• %s: %s %s\n", player.name, vote.points, t("vote.abstained"))
+			points = append(points, vote.points)
+			voted++
+		} else if vote.riskSelected {
+			fmt.Fprintf(&s, "• %s: %s %s\n", player.name, vote.points, t("vote.risk", vote.risk))
+			points = append(points, vote.points)
 			voted++
+		} else if vote.selected {
+			fmt.Fprintf(&s, "• %s: %s\n", player.name, vote.points)
+			points = append(points, vote.points)
+			voted++
+		} else if vote.committed {
+			fmt.Fprintf(&s, "• %s: 🔒\n", player.name)
 		} else {
-			fmt.Fprintf(&s, "• %s: no vote\n", name)
+			fmt.Fprintf(&s, "• %s: %s\n", player.name, t("player.noVote"))
 		}
 	}
 
 	// Show statistics if there are votes
 	if voted > 0 {
-		s.WriteString(showFinalVotes(points, voted))
+		if options.Risk {
+			s.WriteString(showDualVotes(collectDimensions()))
+		} else {
+			s.WriteString(showFinalVotes(points, voted, connectedPlayers()))
+		}
+		s.WriteString(roundTimingView())
+		s.WriteString(leaderboardView())
 	}
 
 	return s.String()
@@ -181,25 +618,106 @@ func (p playerView) showResults() string {
 // (during voting) or the results panel (after reveal). Implements the tea.Model interface.
 func (p playerView) View() string {
 	var s strings.Builder
-	fmt.Fprintf(&s, "🎲 Showdown - Player: %s\n\n", p.name)
+	s.WriteString(t("player.title", p.name) + "\n\n")
 
 	state.mu.RLock()
-	revealed := state.revealed
+	s.WriteString(storyView())
+	if state.announcement != "" {
+		fmt.Fprintf(&s, "📢 %s\n\n", state.announcement)
+	}
+
+	// Show the latest whisper until it expires
+	if p.whisper != "" && time.Now().Before(p.whisperUntil) {
+		fmt.Fprintf(&s, "🤫 %s\n\n", p.whisper)
+	}
+
+	revealed := state.masterRevealed
+	shared := state.playersRevealed
+	countdown := countdownLeft(time.Now())
+	var vote playerVote
+	if player, exists := state.players[playerKey(p.name)]; exists {
+		vote = player.vote()
+	}
+	s.WriteString(timerView())
+	roster := rosterView(p.name)
+	chat := chatView()
 	state.mu.RUnlock()
 
+	if vote.ready {
+		s.WriteString(t("player.ready") + "\n\n")
+	}
+	if vote.committed {
+		s.WriteString(t("player.sealed") + "\n\n")
+	}
+
 	if revealed {
-		s.WriteString(p.showResults())
+		s.WriteString(t("player.closed") + "\n\n")
+		if shared && countdown > 0 {
+			s.WriteString(revealingView(countdown) + "\n\n")
+		} else if shared {
+			s.WriteString(p.showResults())
+		} else {
+			s.WriteString(t("player.waitShare") + "\n\n")
+		}
 	} else {
 		s.WriteString(p.list.View() + "\n\n")
-		if p.selected != "" {
-			fmt.Fprintf(&s, "Selected: %s\n", p.selected)
+		if p.selected != "" && vote.confidence != confidenceNone {
+			s.WriteString(t("player.selected", p.selected) + " " + t("vote.confidence", vote.confidence.label()) + "\n")
+		} else if p.selected != "" {
+			s.WriteString(t("player.selected", p.selected) + "\n")
 		}
+		// Confirm the vote only once the game state has it
+		if p.selected != "" && vote.selected && vote.points == p.selected {
+			s.WriteString(t("player.recorded") + "\n")
+		}
+		if p.risk != "" {
+			s.WriteString(t("player.risk", p.risk) + "\n")
+		}
+	}
+
+	// In reaction mode "?" reacts and the help is toggled with "h"
+	keys := p.keys
+	if revealed {
+		keys = keys.reactionMode()
 	}
 
-	s.WriteString("\nPress q to quit")
+	s.WriteString(roster)
+	s.WriteString(chat)
+	if p.idleWarning(time.Now()) {
+		s.WriteString("\n" + focusStyle.Render(t("player.idle")))
+	} else if p.confirmQuit {
+		s.WriteString("\n" + focusStyle.Render(t("player.confirmQuit")))
+	} else if p.chatting {
+		s.WriteString(p.chat.View() + "\n")
+		s.WriteString(helpStyle(t("player.chatHint") + "\n"))
+	} else if p.showHelp {
+		s.WriteString("\n" + p.help.View(keys))
+	} else {
+		s.WriteString("\n" + t("player.footer", keys.Ready.Help().Key, keys.Confidence.Help().Key, reactionKeys(revealed),
+			keys.Chat.Help().Key, keys.Help.Help().Key, keys.Quit.Help().Key))
+	}
 	return lipgloss.NewStyle().Padding(1).Render(s.String())
 }
 
+// rosterView renders the names of the other connected players, without their
+// votes, so players know when the team is assembled. Offline players and the
+// viewing player are left out. Callers must hold state.mu.
+func rosterView(self string) string {
+	var names []string
+	for _, key := range sortedPlayerKeys() {
+		player := state.players[key]
+		if key == playerKey(self) || player.offline {
+			continue
+		}
+		names = append(names, player.name)
+	}
+
+	if len(names) == 0 {
+		return t("player.alone") + "\n"
+	}
+	return t("player.roster", strings.Join(names, ", ")) + "\n"
+}
+
 // additionalDelegateKeys creates a list delegate with custom key bindings for
 // the help display. It configures the delegate's help functions to show the
 // choose key binding in both short and full help views.
@@ -222,17 +740,19 @@ func additionalDelegateKeys(keys *delegateKeyMap) list.DefaultDelegate {
 // initPlayerView creates and initializes a new player view with the point
 // selection list and registers the player in the global game state.
 func initPlayerView(playerName string, session ssh.Session) (tea.Model, tea.Cmd) {
-	items := make([]list.Item, len(pointOptions))
-	for i, p := range pointOptions {
-		items[i] = PointItem{value: p}
-	}
+	state.mu.RLock()
+	deck := activeDeck()
+	deckVersion := state.deckVersion
+	state.mu.RUnlock()
+	items := pointItems(deck)
 
 	selectedColor := lipgloss.Color(catppuccinMauve)
 	d := additionalDelegateKeys(newDelegateKeyMap())
 	d.Styles.SelectedTitle = d.Styles.SelectedTitle.Foreground(selectedColor).BorderLeftForeground(selectedColor)
 	d.Styles.SelectedDesc = d.Styles.SelectedTitle
-	l := list.New(items, d, 20, 20)
-	l.Title = "Select Points"
+	l := list.New(items, d, listWidth, listHeight)
+	l.Title = t("player.list")
+	l.SetStatusBarItemName(t("player.cards.one"), t("player.cards.other"))
 	l.SetShowTitle(true)
 	l.SetFilteringEnabled(false) // no filtering needed
 	// styling of the list title
@@ -245,31 +765,330 @@ func initPlayerView(playerName string, session ssh.Session) (tea.Model, tea.Cmd)
 	l.Styles.StatusBar = lipgloss.NewStyle().
 		Foreground(lipgloss.Color(catppuccinBlue))
 
+	chat := textinput.New()
+	chat.Prompt = "Chat: "
+	chat.CharLimit = maxMessageLength
+	chat.Cursor.Style = focusStyle
+	chat.PromptStyle = focusStyle
+
 	p := playerView{
-		name: playerName,
-		list: l,
+		name:        playerName,
+		list:        l,
+		cardKeys:    cardKeys(deck),
+		chat:        chat,
+		out:         session,
+		help:        help.New(),
+		deckVersion: deckVersion,
+		lastActive:  time.Now(),
 	}
+	p.keys = newKeyMapPlayer(p.cardKeys)
+	p.help.ShowAll = true
 
-	state.mu.Lock()
-	state.players[playerName] = &playerState{
-		session: session,
+	player := addPlayer(playerName, session)
+	p.whispers = player.whispers
+
+	// Restore the vote of a reconnecting player, without ringing for a timer
+	// that expired before joining
+	state.mu.RLock()
+	vote := player.vote()
+	p.belledTimer = expiredTimer()
+	state.mu.RUnlock()
+	if vote.selected {
+		p.markVote(vote.points)
+	}
+	if vote.riskSelected {
+		p.risk = vote.risk
+		p.markCard(vote.risk)
 	}
+
+	return p, waitForWhisper(p.whispers)
+}
+
+// checkJoin validates a player name and reports whether the player may join,
+// rejecting names that are already taken (ignoring casing) by an online player,
+// new players while joins are locked, and joins beyond the player limit. The
+// session is nil for players joining through the web gateway.
+func checkJoin(name string, session ssh.Session) error {
+	if err := validatePlayerName(name); err != nil {
+		return err
+	}
+
+	state.mu.RLock()
+	player, exists := state.players[playerKey(name)]
+	rejoin := exists && canRejoin(player, session)
+	duplicate := exists && sameKey(player, session)
+	playerCount := connectedPlayers()
+	frozen := state.frozen
+	state.mu.RUnlock()
+
+	// Players who lost their connection may reconnect with their name
+	if rejoin {
+		return nil
+	}
+	if duplicate {
+		return fmt.Errorf("already connected elsewhere")
+	}
+	if exists {
+		return fmt.Errorf("name already taken")
+	}
+	if frozen {
+		return errJoinsLocked
+	}
+	if limit := playerCap(); playerCount >= limit {
+		return errors.New(plural("join.full", limit))
+	}
+
+	return nil
+}
+
+// errJoinsLocked is returned to new players while the Scrum Master has locked
+// joins.
+var errJoinsLocked = errors.New("Session in progress, joins are locked")
+
+// joinsLocked reports whether a new connection can't join at all: joins are
+// locked and no player who lost their connection could be reconnecting.
+func joinsLocked() bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	if !state.frozen {
+		return false
+	}
+	for _, player := range state.players {
+		if player.offline {
+			return false
+		}
+	}
+	return true
+}
+
+// connectedPlayers returns the number of players who haven't lost their
+// connection. Callers must hold state.mu.
+func connectedPlayers() int {
+	connected := 0
+	for _, player := range state.players {
+		if !player.offline {
+			connected++
+		}
+	}
+	return connected
+}
+
+// toggleFrozen locks or unlocks joining for new players and returns whether
+// joins are now locked.
+func toggleFrozen() bool {
+	state.mu.Lock()
+	state.frozen = !state.frozen
+	frozen := state.frozen
 	state.mu.Unlock()
 
-	return p, nil
+	if frozen {
+		logActivity("Joins locked")
+	} else {
+		logActivity("Joins unlocked")
+	}
+	return frozen
+}
+
+// canRejoin reports whether a session may take over an existing player: when
+// the player is offline and the new session uses the SSH key they joined with,
+// or when their previous connection is still around, the new session uses the
+// same SSH key, and duplicate sessions take over. An offline player who joined
+// without a key is reclaimed by name alone, while an online one can't be taken
+// over. Callers must hold state.mu.
+func canRejoin(player *playerState, session ssh.Session) bool {
+	if player.offline {
+		if player.fingerprint == "" {
+			return true
+		}
+		return session != nil && player.fingerprint == keyFingerprint(session.PublicKey())
+	}
+	return options.DuplicateSessions == duplicateTakeover && sameKey(player, session)
+}
+
+// sameKey reports whether the session uses the same SSH key as the player's
+// current session. Callers must hold state.mu.
+func sameKey(player *playerState, session ssh.Session) bool {
+	if session == nil || player.session == nil {
+		return false
+	}
+	return ssh.KeysEqual(player.session.PublicKey(), session.PublicKey())
+}
+
+// addPlayer registers a player in the global game state. The session is nil
+// for players connected through the web gateway. A player who lost their
+// connection or was restored from the state file is taken over, keeping their
+// vote; a stale connection still attached to them is disconnected.
+func addPlayer(name string, session ssh.Session) *playerState {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if player, exists := state.players[playerKey(name)]; exists && canRejoin(player, session) {
+		if player.whispers != nil {
+			close(player.whispers)
+		}
+		if stale := player.session; stale != nil {
+			go disconnectSession(name, stale)
+		}
+		player.session = session
+		player.whispers = make(chan string, whisperBuffer)
+		player.offline = false
+		if player.fingerprint == "" && session != nil {
+			player.fingerprint = keyFingerprint(session.PublicKey())
+		}
+		logActivity("%s reconnected", player.name)
+		return player
+	}
+
+	player := &playerState{
+		name:     name,
+		color:    playerColor(name),
+		whispers: make(chan string, whisperBuffer),
+		session:  session,
+	}
+	if session != nil {
+		player.fingerprint = keyFingerprint(session.PublicKey())
+	}
+	state.putPlayer(playerKey(name), player)
+	state.joined[playerKey(name)] = true
+	logActivity("%s joined", name)
+	events.emit(event{Type: "join", Round: state.round, Player: name})
+
+	return player
+}
+
+// toggleReady flips the ready check-in of the named player.
+func toggleReady(name string) {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	if player, exists := state.players[playerKey(name)]; exists {
+		player.mu.Lock()
+		player.ready = !player.ready
+		player.mu.Unlock()
+	}
+}
+
+// markOffline marks the player connected through the given SSH session as
+// offline after the session ended, keeping their vote for when they reconnect.
+// Callers must hold state.mu.
+func markOffline(s ssh.Session) {
+	for _, player := range state.players {
+		if player.session != s {
+			continue
+		}
+		if player.whispers != nil {
+			close(player.whispers)
+			player.whispers = nil
+		}
+		player.session = nil
+		player.offline = true
+		log.Info("Player disconnected", "player", player.name)
+		logActivity("%s disconnected", player.name)
+	}
+}
+
+// removePlayer deletes a player from the game state and closes their whisper
+// channel, ending the command waiting on it. Callers must hold state.mu.
+func removePlayer(key string) {
+	player, exists := state.players[key]
+	if !exists {
+		return
+	}
+	if player.whispers != nil {
+		close(player.whispers)
+	}
+	state.deletePlayer(key)
+	logActivity("%s left", player.name)
+}
+
+// castVote records the points for the named player. It returns false without
+// changing anything once votes are revealed or if the player is unknown.
+//
+// Only the read lock of the game state is held, which is enough to keep votes
+// from being revealed or cleared meanwhile, so voting doesn't block rendering.
+func castVote(name, points string) bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	if state.masterRevealed {
+		return false
+	}
+	player, exists := state.players[playerKey(name)]
+	if !exists {
+		return false
+	}
+	player.mu.Lock()
+	if player.selected && !player.abstained && player.points != points {
+		player.voteChanges++
+	}
+	player.points = points
+	player.selected = true
+	player.abstained = false
+	player.commit = ""
+	player.changedAt = time.Now()
+	if player.votedAt.IsZero() {
+		player.votedAt = player.changedAt
+	}
+	player.mu.Unlock()
+	logActivity("%s voted", player.name)
+	events.emit(event{Type: "vote", Round: state.round, Player: player.name, Points: points})
+
+	// Voting time starts with the first card selected in the round
+	state.votingStartMu.Lock()
+	if state.votingStart.IsZero() {
+		state.votingStart = time.Now()
+	}
+	state.votingStartMu.Unlock()
+	return true
+}
+
+// retractVote withdraws the vote of the named player, so they count as not
+// having voted. It returns false once votes are revealed, if the player is
+// unknown, or if they haven't voted.
+func retractVote(name string) bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	if state.masterRevealed {
+		return false
+	}
+	player, exists := state.players[playerKey(name)]
+	if !exists {
+		return false
+	}
+	player.mu.Lock()
+	selected := player.selected
+	if selected {
+		// Voting again after retracting changes the vote as well
+		player.voteChanges++
+		player.changedAt = time.Now()
+	}
+	player.points = ""
+	player.selected = false
+	player.risk = ""
+	player.riskSelected = false
+	player.abstained = false
+	player.votedAt = time.Time{}
+	player.mu.Unlock()
+	if !selected {
+		return false
+	}
+	logActivity("%s retracted their vote", player.name)
+	return true
 }
 
 // initialNameInputView creates the name input form for new players joining
-// the session, with styled text input and a 30-character limit.
+// the session, with styled text input as wide as the name limit.
 func initialNameInputView(session ssh.Session) nameInputView {
 	ti := textinput.New()
 	ti.Cursor.Style = focusStyle
-	ti.Placeholder = "Enter your name"
+	ti.Placeholder = options.NamePlaceholder
 	ti.Focus()
 	ti.PromptStyle = focusStyle
 	ti.TextStyle = focusStyle
-	ti.CharLimit = 30
-	ti.Width = 30
+	ti.CharLimit = options.NameLimit
+	ti.Width = options.NameLimit
 
 	return nameInputView{
 		textInput: ti,
@@ -295,29 +1114,11 @@ func (v nameInputView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyEnter:
 			name := strings.TrimSpace(v.textInput.Value())
 
-			// Validate player name
-			if err := validatePlayerName(name); err != nil {
+			if err := checkJoin(name, v.session); err != nil {
 				v.err = err
 				return v, nil
 			}
 
-			state.mu.RLock()
-			_, exists := state.players[name]
-			playerCount := len(state.players)
-			state.mu.RUnlock()
-
-			// Check if name is already taken
-			if exists {
-				v.err = fmt.Errorf("name already taken")
-				return v, nil
-			}
-
-			// Check player limit
-			if playerCount >= maxPlayers {
-				v.err = fmt.Errorf("game is full (maximum %d players)", maxPlayers)
-				return v, nil
-			}
-
 			return initPlayerView(name, v.session)
 		case tea.KeyCtrlC:
 			return v, tea.Quit
@@ -334,11 +1135,13 @@ func (v nameInputView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // and any validation error messages. Implements the tea.Model interface.
 func (v nameInputView) View() string {
 	var s strings.Builder
-	s.WriteString("Welcome to Showdown!\n\n")
+	if options.Welcome != "" {
+		s.WriteString(options.Welcome + "\n\n")
+	}
 	s.WriteString(v.textInput.View() + "\n\n")
-	s.WriteString(helpStyle("Press Enter to continue\n"))
+	s.WriteString(helpStyle(t("name.continue") + "\n"))
 	if v.err != nil {
-		s.WriteString("\nError: " + v.err.Error() + "\n")
+		s.WriteString("\n" + t("name.error", v.err.Error()) + "\n")
 	}
 	return lipgloss.NewStyle().Padding(1).Render(s.String())
 }
@@ -1,7 +1,15 @@
 package main
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // TestPointItem tests the PointItem interface implementation
@@ -82,16 +90,16 @@ func TestDelegateKeyMapFullHelp(t *testing.T) {
 func TestPlayerStateManagement(t *testing.T) {
 	// Clear any existing players
 	state.mu.Lock()
-	state.players = make(map[string]*playerState)
+	state.resetPlayers()
 	state.mu.Unlock()
 
 	// Add a player
 	testPlayerName := "test_player"
 	state.mu.Lock()
-	state.players[testPlayerName] = &playerState{
+	state.putPlayer(testPlayerName, &playerState{
 		points:   "",
 		selected: false,
-	}
+	})
 	state.mu.Unlock()
 
 	// Verify player was added
@@ -128,7 +136,7 @@ func TestPlayerStateManagement(t *testing.T) {
 
 	// Remove player
 	state.mu.Lock()
-	delete(state.players, testPlayerName)
+	state.deletePlayer(testPlayerName)
 	state.mu.Unlock()
 
 	// Verify player was removed
@@ -145,8 +153,8 @@ func TestPlayerStateManagement(t *testing.T) {
 func TestPlayerVotingScenario(t *testing.T) {
 	// Clear and setup
 	state.mu.Lock()
-	state.players = make(map[string]*playerState)
-	state.revealed = false
+	state.resetPlayers()
+	state.masterRevealed = false
 	state.mu.Unlock()
 
 	// Add multiple players
@@ -155,10 +163,10 @@ func TestPlayerVotingScenario(t *testing.T) {
 
 	for i, name := range players {
 		state.mu.Lock()
-		state.players[name] = &playerState{
+		state.putPlayer(name, &playerState{
 			points:   votes[i],
 			selected: true,
-		}
+		})
 		state.mu.Unlock()
 	}
 
@@ -178,13 +186,13 @@ func TestPlayerVotingScenario(t *testing.T) {
 
 	// Reveal votes
 	state.mu.Lock()
-	state.revealed = true
+	state.masterRevealed = true
 	state.mu.Unlock()
 
 	// Verify revealed state
 	state.mu.RLock()
-	if !state.revealed {
-		t.Errorf("state.revealed = false, want true")
+	if !state.masterRevealed {
+		t.Errorf("state.masterRevealed = false, want true")
 	}
 	state.mu.RUnlock()
 
@@ -193,8 +201,8 @@ func TestPlayerVotingScenario(t *testing.T) {
 
 	// Verify state was cleared
 	state.mu.RLock()
-	if state.revealed {
-		t.Errorf("after clear: state.revealed = true, want false")
+	if state.masterRevealed {
+		t.Errorf("after clear: state.masterRevealed = true, want false")
 	}
 	for name, player := range state.players {
 		if player.selected {
@@ -211,7 +219,7 @@ func TestPlayerVotingScenario(t *testing.T) {
 func TestNameValidation(t *testing.T) {
 	// Clear players
 	state.mu.Lock()
-	state.players = make(map[string]*playerState)
+	state.resetPlayers()
 	state.mu.Unlock()
 
 	tests := []struct {
@@ -246,7 +254,7 @@ func TestNameValidation(t *testing.T) {
 	// Test duplicate name scenario
 	duplicateName := "duplicate"
 	state.mu.Lock()
-	state.players[duplicateName] = &playerState{}
+	state.putPlayer(duplicateName, &playerState{})
 	state.mu.Unlock()
 
 	state.mu.RLock()
@@ -257,3 +265,976 @@ func TestNameValidation(t *testing.T) {
 		t.Errorf("duplicate player name should exist in state")
 	}
 }
+
+// TestDuplicateNameCaseInsensitive tests that names differing only in casing are rejected
+func TestDuplicateNameCaseInsensitive(t *testing.T) {
+	state.mu.Lock()
+	state.resetPlayers()
+	state.putPlayer(playerKey("alice"), &playerState{name: "alice"})
+	state.mu.Unlock()
+
+	v := initialNameInputView(nil)
+	v.textInput.SetValue("ALICE")
+
+	model, _ := v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	got, ok := model.(nameInputView)
+	if !ok {
+		t.Fatalf("Update() returned %T, want nameInputView", model)
+	}
+	if got.err == nil {
+		t.Errorf("joining as ALICE when alice exists: err = nil, want error")
+	}
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if len(state.players) != 1 {
+		t.Errorf("players count = %d, want 1", len(state.players))
+	}
+	if player := state.players[playerKey("ALICE")]; player.name != "alice" {
+		t.Errorf("player display name = %s, want alice", player.name)
+	}
+}
+
+// TestNameInputText tests that the welcome banner and placeholder of the name
+// input can be customized
+func TestNameInputText(t *testing.T) {
+	tests := []struct {
+		name        string
+		welcome     string
+		placeholder string
+	}{
+		{"defaults", options.Welcome, options.NamePlaceholder},
+		{"custom", "Willkommen bei Showdown!", "Dein Name"},
+		{"no banner", "", "Your name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(welcome, placeholder string) {
+				options.Welcome, options.NamePlaceholder = welcome, placeholder
+			}(options.Welcome, options.NamePlaceholder)
+			options.Welcome, options.NamePlaceholder = tt.welcome, tt.placeholder
+
+			v := initialNameInputView(nil)
+			if v.textInput.Placeholder != tt.placeholder {
+				t.Errorf("Placeholder = %q, want %q", v.textInput.Placeholder, tt.placeholder)
+			}
+			view := v.View()
+			if tt.welcome != "" && !strings.Contains(view, tt.welcome) {
+				t.Errorf("View() doesn't show the welcome banner %q:\n%s", tt.welcome, view)
+			}
+			if tt.welcome == "" && strings.Contains(view, "Welcome") {
+				t.Errorf("View() shows a welcome banner when it's empty:\n%s", view)
+			}
+		})
+	}
+}
+
+// TestPlayerReactions tests setting a reaction and clearing it for a new round
+func TestPlayerReactions(t *testing.T) {
+	state.mu.Lock()
+	state.resetPlayers()
+	state.masterRevealed = true
+	state.mu.Unlock()
+
+	model, _ := initPlayerView("alice", nil)
+
+	for key, want := range reactionOptions {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+
+		state.mu.RLock()
+		got := state.players[playerKey("alice")].reaction
+		state.mu.RUnlock()
+
+		if got != want {
+			t.Errorf("reaction after %q = %s, want %s", key, got, want)
+		}
+	}
+
+	clearPlayerState()
+
+	state.mu.RLock()
+	if got := state.players[playerKey("alice")].reaction; got != "" {
+		t.Errorf("reaction after clearPlayerState() = %s, want empty", got)
+	}
+	state.mu.RUnlock()
+
+	// While voting is open "?" votes unknown instead of reacting
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if alice := state.players[playerKey("alice")].vote(); alice.reaction != "" || alice.points != "?" {
+		t.Errorf("alice after ? while voting = %+v, want a ? vote without reaction", alice)
+	}
+}
+
+// TestReactionModeHelp tests that the help moves to "h" once the votes are
+// revealed, where "?" reacts
+func TestReactionModeHelp(t *testing.T) {
+	state = newGameState()
+	model, _ := initPlayerView("alice", nil)
+	if view := model.View(); !strings.Contains(view, "+/- to react") {
+		t.Errorf("View() while voting doesn't offer +/- to react:\n%s", view)
+	}
+
+	state.mu.Lock()
+	state.masterRevealed = true
+	state.mu.Unlock()
+	view := model.View()
+	if !strings.Contains(view, "+/-/? to react") || !strings.Contains(view, "h for help") {
+		t.Errorf("View() in reaction mode doesn't offer ? to react and h for help:\n%s", view)
+	}
+}
+
+// TestVotingClosedAfterReveal tests that enter after reveal does not change the vote
+func TestVotingClosedAfterReveal(t *testing.T) {
+	state.mu.Lock()
+	state.resetPlayers()
+	state.masterRevealed = false
+	state.mu.Unlock()
+
+	model, _ := initPlayerView("alice", nil)
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	state.mu.Lock()
+	want := state.players[playerKey("alice")].points
+	state.masterRevealed = true
+	state.mu.Unlock()
+
+	// Move the cursor to another card and try to vote again
+	p := model.(playerView)
+	p.list.Select(3)
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if got := state.players[playerKey("alice")].points; got != want {
+		t.Errorf("points after post-reveal enter = %s, want %s", got, want)
+	}
+}
+
+// TestConcurrentVotingAndRendering tests votes, reactions, and ready check-ins
+// racing with the master rendering its view; run it with -race
+func TestConcurrentVotingAndRendering(t *testing.T) {
+	state = newGameState()
+	names := make([]string, 20)
+	for i := range names {
+		names[i] = fmt.Sprintf("player%02d", i)
+		addPlayer(names[i], nil)
+	}
+
+	done := make(chan struct{})
+	var render sync.WaitGroup
+	render.Add(1)
+	go func() {
+		defer render.Done()
+		m := newMasterView()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = m.View()
+				_ = currentAPIState()
+			}
+		}
+	}()
+
+	var voters sync.WaitGroup
+	for i, name := range names {
+		voters.Add(1)
+		go func() {
+			defer voters.Done()
+			for j := 0; j < 50; j++ {
+				castVote(name, pointOptions[(i+j)%len(pointOptions)])
+				toggleReady(name)
+			}
+		}()
+	}
+	voters.Wait()
+	close(done)
+	render.Wait()
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	for _, name := range names {
+		if vote := state.players[name].vote(); !vote.selected {
+			t.Errorf("player %s has no vote", name)
+		}
+	}
+	if state.votingStart.IsZero() {
+		t.Errorf("votingStart not set by the first vote")
+	}
+}
+
+// BenchmarkVoteWhileRendering measures voting while the master view renders
+// continuously, which contends on the game state lock.
+func BenchmarkVoteWhileRendering(b *testing.B) {
+	state = newGameState()
+	names := make([]string, 50)
+	for i := range names {
+		names[i] = fmt.Sprintf("player%02d", i)
+		addPlayer(names[i], nil)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		m := newMasterView()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = m.View()
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			castVote(names[i%len(names)], pointOptions[i%len(pointOptions)])
+			i++
+		}
+	})
+}
+
+// TestReconnectKeepsVote tests that a player whose connection dropped can
+// reconnect with their name and gets their vote back
+func TestReconnectKeepsVote(t *testing.T) {
+	state = newGameState()
+	key := newTestPublicKey(t)
+	first := &stubSession{key: key}
+	initPlayerView("alice", first)
+	castVote("alice", "5")
+
+	// The connection drops and the session middleware cleans up
+	state.mu.Lock()
+	markOffline(first)
+	state.mu.Unlock()
+
+	if player := state.players["alice"]; !player.offline || player.session != nil || player.whispers != nil {
+		t.Fatalf("alice after disconnect = %+v, want offline without session", player)
+	}
+
+	// Only the key alice joined with takes over her seat
+	if err := checkJoin("alice", &stubSession{key: newTestPublicKey(t)}); err == nil {
+		t.Errorf("checkJoin() with another key err = nil, want name already taken")
+	}
+	if err := checkJoin("alice", &stubSession{}); err == nil {
+		t.Errorf("checkJoin() without key err = nil, want name already taken")
+	}
+
+	second := &stubSession{key: key}
+	if err := checkJoin("alice", second); err != nil {
+		t.Fatalf("checkJoin() on reconnect err = %v, want nil", err)
+	}
+	model, _ := initPlayerView("alice", second)
+	p := model.(playerView)
+
+	if p.selected != "5" {
+		t.Errorf("playerView.selected = %q, want 5", p.selected)
+	}
+	if item, ok := p.list.SelectedItem().(PointItem); !ok || item.value != "5" {
+		t.Errorf("list selection = %v, want 5", p.list.SelectedItem())
+	}
+	player := state.players["alice"]
+	if player.offline || player.session != second || player.points != "5" || !player.selected {
+		t.Errorf("alice after reconnect = %+v, want online with vote 5", player)
+	}
+	if len(state.players) != 1 {
+		t.Errorf("players = %d after reconnect, want 1", len(state.players))
+	}
+}
+
+// TestReconnectWithSameKey tests that a stale connection is replaced by a new
+// one with the same SSH key, and that other keys can't take over the name
+func TestReconnectWithSameKey(t *testing.T) {
+	state = newGameState()
+	key := newTestPublicKey(t)
+	stale := &stubSession{key: key}
+	initPlayerView("bob", stale)
+	castVote("bob", "8")
+
+	if err := checkJoin("bob", &stubSession{key: newTestPublicKey(t)}); err == nil {
+		t.Errorf("checkJoin() with another key err = nil, want name already taken")
+	}
+	if err := checkJoin("bob", &stubSession{}); err == nil {
+		t.Errorf("checkJoin() without key err = nil, want name already taken")
+	}
+
+	fresh := &stubSession{key: key}
+	if err := checkJoin("bob", fresh); err != nil {
+		t.Fatalf("checkJoin() with the same key err = %v, want nil", err)
+	}
+	model, _ := initPlayerView("bob", fresh)
+	if p := model.(playerView); p.selected != "8" {
+		t.Errorf("playerView.selected = %q, want 8", p.selected)
+	}
+	if state.players["bob"].session != fresh {
+		t.Errorf("bob's session was not replaced")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !stale.isClosed() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !stale.isClosed() {
+		t.Errorf("stale session not closed")
+	}
+}
+
+// TestKeylessReconnect tests that a player who joined without a key gets their
+// seat back by name after losing the connection, and that their offline seat
+// doesn't count toward the player limit
+func TestKeylessReconnect(t *testing.T) {
+	state = newGameState()
+	options.MaxPlayers = 1
+	defer func() { options.MaxPlayers = 0 }()
+	first := &stubSession{}
+	initPlayerView("carol", first)
+	castVote("carol", "3")
+
+	state.mu.Lock()
+	markOffline(first)
+	state.mu.Unlock()
+
+	if err := checkJoin("dave", &stubSession{}); err != nil {
+		t.Errorf("checkJoin(dave) with carol offline err = %v, want nil", err)
+	}
+
+	second := &stubSession{}
+	if err := checkJoin("carol", second); err != nil {
+		t.Fatalf("checkJoin() on keyless reconnect err = %v, want nil", err)
+	}
+	initPlayerView("carol", second)
+	player := state.players["carol"]
+	if player.offline || player.session != second || player.points != "3" || !player.selected {
+		t.Errorf("carol after reconnect = %+v, want online with vote 3", player)
+	}
+	if len(state.players) != 1 {
+		t.Errorf("players = %d after reconnect, want 1", len(state.players))
+	}
+
+	// Online, the keyless seat can't be taken over
+	if err := checkJoin("carol", &stubSession{}); err == nil {
+		t.Errorf("checkJoin() for online keyless carol err = nil, want name already taken")
+	}
+}
+
+// TestCardKeys tests the quick-vote key of each card of the deck
+func TestCardKeys(t *testing.T) {
+	want := map[string]string{
+		".": "0.5", "1": "1", "2": "2", "3": "3", "5": "5",
+		"8": "8", "0": "10", "?": "?",
+	}
+	got := cardKeys(pointOptions)
+	if len(got) != len(want) {
+		t.Errorf("cardKeys() = %v, want %v", got, want)
+	}
+	for k, card := range want {
+		if got[k] != card {
+			t.Errorf("cardKeys()[%q] = %q, want %q", k, got[k], card)
+		}
+	}
+	// "?" only reacts once the votes are revealed and cards can't be voted
+	for k := range got {
+		if _, ok := reactionFor(k, false); ok {
+			t.Errorf("card key %q collides with a reaction", k)
+		}
+	}
+}
+
+// TestQuickVote tests that pressing a card's key votes for it
+func TestQuickVote(t *testing.T) {
+	state = newGameState()
+	model, _ := initPlayerView("alice", nil)
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"5", "5"},
+		{".", "0.5"},
+		{"?", "?"},
+		{"0", "10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)})
+
+			player := state.players["alice"]
+			if player.points != tt.want || !player.selected {
+				t.Errorf("after %q points = %q selected = %v, want %q", tt.key, player.points, player.selected, tt.want)
+			}
+			if p := model.(playerView); p.selected != tt.want {
+				t.Errorf("playerView.selected = %q, want %q", p.selected, tt.want)
+			}
+		})
+	}
+
+	// Arrow and enter keep working
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := state.players["alice"].points; got != "8" {
+		t.Errorf("points after up and enter = %q, want 8", got)
+	}
+}
+
+// TestVotedCardStaysMarked tests that the list selects and checks the voted
+// card, and forgets it once the round is cleared
+func TestVotedCardStaysMarked(t *testing.T) {
+	state = newGameState()
+	model, _ := initPlayerView("alice", nil)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("8")})
+
+	p := model.(playerView)
+	vote := state.players["alice"].points
+	if want := slices.Index(pointOptions, vote); p.list.Index() != want {
+		t.Errorf("list index = %d, want %d for vote %s", p.list.Index(), want, vote)
+	}
+	for _, item := range p.list.Items() {
+		point := item.(PointItem)
+		if point.voted != (point.value == vote) {
+			t.Errorf("card %s voted = %v with vote %s", point.value, point.voted, vote)
+		}
+	}
+	if item := p.list.SelectedItem().(PointItem); item.Title() != vote+" ✓" {
+		t.Errorf("selected title = %q, want %q", item.Title(), vote+" ✓")
+	}
+
+	clearPlayerState()
+	model, _ = model.Update(tickMsg{})
+	for _, item := range model.(playerView).list.Items() {
+		if item.(PointItem).voted {
+			t.Errorf("card %s still marked after clearPlayerState()", item.(PointItem).value)
+		}
+	}
+}
+
+// TestRoster tests that the roster lists the other online players but not the
+// viewing player or any votes
+func TestRoster(t *testing.T) {
+	state = newGameState()
+	setTestPlayers(map[string]*playerState{
+		"alice": {name: "Alice", points: "5", selected: true},
+		"bob":   {name: "Bob", points: "8", selected: true},
+		"carol": {name: "Carol", offline: true},
+	})
+
+	tests := []struct {
+		self string
+		want string
+	}{
+		{"Alice", "👥 Also here: Bob\n"},
+		{"bob", "👥 Also here: Alice\n"},
+		{"dave", "👥 Also here: Alice, Bob\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.self, func(t *testing.T) {
+			state.mu.RLock()
+			got := rosterView(tt.self)
+			state.mu.RUnlock()
+			if got != tt.want {
+				t.Errorf("rosterView(%q) = %q, want %q", tt.self, got, tt.want)
+			}
+		})
+	}
+
+	state = newGameState()
+	model, _ := initPlayerView("alice", nil)
+	if view := model.View(); !strings.Contains(view, "👥 Nobody else has joined yet") {
+		t.Errorf("View() of the only player doesn't say nobody else joined:\n%s", view)
+	}
+}
+
+// TestDuplicateSessions tests that a second session with the same key takes
+// over the player, closing the old session, or is rejected
+func TestDuplicateSessions(t *testing.T) {
+	tests := []struct {
+		mode         string
+		wantErr      bool
+		wantTakeover bool
+	}{
+		{duplicateTakeover, false, true},
+		{duplicateReject, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			state = newGameState()
+			options.DuplicateSessions = tt.mode
+			defer func() { options.DuplicateSessions = duplicateTakeover }()
+
+			key := newTestPublicKey(t)
+			old := &stubSession{key: key}
+			initPlayerView("bob", old)
+			player := state.players["bob"]
+
+			fresh := &stubSession{key: key}
+			err := checkJoin("bob", fresh)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkJoin() of second session err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if err.Error() != "already connected elsewhere" {
+					t.Errorf("checkJoin() err = %q, want already connected elsewhere", err)
+				}
+				if old.isClosed() || player.session != old {
+					t.Errorf("rejected session changed the connected player")
+				}
+				return
+			}
+
+			initPlayerView("bob", fresh)
+			if len(state.players) != 1 || state.players["bob"] != player || player.session != fresh {
+				t.Errorf("second session doesn't own bob's player state")
+			}
+			deadline := time.Now().Add(time.Second)
+			for !old.isClosed() && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if !old.isClosed() {
+				t.Errorf("old session not closed after takeover")
+			}
+		})
+	}
+}
+
+// TestKeyMapPlayerBindings tests that all player key bindings are properly
+// configured
+func TestKeyMapPlayerBindings(t *testing.T) {
+	tests := []struct {
+		name    string
+		binding key.Binding
+		keys    []string
+	}{
+		{"previous card binding", keysPlayer.Up, []string{"up", "k"}},
+		{"next card binding", keysPlayer.Down, []string{"down", "j"}},
+		{"choose binding", keysPlayer.Choose, []string{"enter"}},
+		{"retract binding", keysPlayer.Retract, []string{"backspace", "delete"}},
+		{"ready binding", keysPlayer.Ready, []string{"r"}},
+		{"confidence binding", keysPlayer.Confidence, []string{"c"}},
+		{"chat binding", keysPlayer.Chat, []string{"t"}},
+		{"help binding", keysPlayer.Help, []string{"?", "h"}},
+		{"quit binding", keysPlayer.Quit, []string{"q", "esc", "ctrl+c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.binding.Enabled() {
+				t.Errorf("%s is not enabled", tt.name)
+			}
+			if got := tt.binding.Keys(); !slices.Equal(got, tt.keys) {
+				t.Errorf("%s keys = %v, want %v", tt.name, got, tt.keys)
+			}
+		})
+	}
+}
+
+// TestKeyMapPlayerUniqueKeys tests that no key is bound twice and that no
+// player key collides with a reaction in reaction mode
+func TestKeyMapPlayerUniqueKeys(t *testing.T) {
+	bound := make(map[string]string)
+	for _, group := range keysPlayer.FullHelp() {
+		for _, binding := range group {
+			for _, k := range binding.Keys() {
+				if other, exists := bound[k]; exists {
+					t.Errorf("key %q is bound to both %q and %q", k, other, binding.Help().Desc)
+				}
+				bound[k] = binding.Help().Desc
+			}
+		}
+	}
+
+	reacting := make(map[string]string)
+	for _, group := range keysPlayer.reactionMode().FullHelp() {
+		for _, binding := range group {
+			for _, k := range binding.Keys() {
+				reacting[k] = binding.Help().Desc
+			}
+		}
+	}
+	for k := range reactionOptions {
+		if desc, exists := reacting[k]; exists {
+			t.Errorf("reaction %q collides with the %q binding", k, desc)
+		}
+	}
+}
+
+// TestKeyMapPlayerShortHelp tests the short help display
+func TestKeyMapPlayerShortHelp(t *testing.T) {
+	shortHelp := keysPlayer.ShortHelp()
+
+	expectedCount := 7 // Choose, Retract, Ready, Confidence, Chat, Help, Quit
+	if len(shortHelp) != expectedCount {
+		t.Errorf("ShortHelp() returned %d bindings, want %d", len(shortHelp), expectedCount)
+	}
+}
+
+// TestKeyMapPlayer tests the bindings of the player's help overlay, which
+// toggles with "h" when "?" is a card
+func TestKeyMapPlayer(t *testing.T) {
+	tests := []struct {
+		name     string
+		deck     []string
+		wantHelp []string
+	}{
+		{"deck with ? card", []string{"1", "2", "?"}, []string{"h"}},
+		{"deck without ? card", []string{"S", "M", "L"}, []string{"?", "h"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := newKeyMapPlayer(cardKeys(tt.deck))
+
+			if got := keys.Help.Keys(); !slices.Equal(got, tt.wantHelp) {
+				t.Errorf("help keys = %v, want %v", got, tt.wantHelp)
+			}
+			for _, card := range tt.deck {
+				for _, group := range keys.FullHelp() {
+					for _, binding := range group {
+						if slices.Contains(binding.Keys(), card) {
+							t.Errorf("card %q collides with the %q binding", card, binding.Help().Desc)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestPlayerHelpOverlay tests that the help model starts with the navigation,
+// vote, retract, and quit bindings and is toggled by the help key
+func TestPlayerHelpOverlay(t *testing.T) {
+	state = newGameState()
+
+	model, _ := initPlayerView("alice", nil)
+	p := model.(playerView)
+
+	if !p.help.ShowAll {
+		t.Errorf("help.ShowAll = false, want the full help")
+	}
+	var descs []string
+	for _, group := range p.keys.FullHelp() {
+		for _, binding := range group {
+			descs = append(descs, binding.Help().Desc)
+		}
+	}
+	want := []string{"previous card", "next card", "choose", "retract vote", "toggle ready", "set confidence", "chat", "toggle help", "quit"}
+	if !slices.Equal(descs, want) {
+		t.Errorf("help bindings = %v, want %v", descs, want)
+	}
+
+	if view := p.View(); strings.Contains(view, "retract vote") {
+		t.Errorf("View() shows the help overlay before toggling it:\n%s", view)
+	}
+	model, _ = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if view := model.View(); !strings.Contains(view, "retract vote") {
+		t.Errorf("View() doesn't show the help overlay:\n%s", view)
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if view := model.View(); strings.Contains(view, "retract vote") {
+		t.Errorf("View() still shows the help overlay after closing it:\n%s", view)
+	}
+}
+
+// TestRetractVote tests withdrawing a vote before the reveal
+func TestRetractVote(t *testing.T) {
+	state = newGameState()
+
+	model, _ := initPlayerView("alice", nil)
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+
+	state.mu.RLock()
+	vote := state.players["alice"].vote()
+	state.mu.RUnlock()
+	if vote.selected || vote.points != "" {
+		t.Errorf("vote after retracting = %q (selected %v), want none", vote.points, vote.selected)
+	}
+	if p := model.(playerView); p.selected != "" {
+		t.Errorf("selected = %q after retracting, want empty", p.selected)
+	}
+
+	if retractVote("alice") {
+		t.Errorf("retractVote() without a vote = true, want false")
+	}
+
+	castVote("alice", "8")
+	revealVotes()
+	if retractVote("alice") {
+		t.Errorf("retractVote() after the reveal = true, want false")
+	}
+}
+
+// TestRevealCountdownHidesResults tests that players only see the results once
+// the suspense countdown of the reveal has ended
+func TestRevealCountdownHidesResults(t *testing.T) {
+	state = newGameState()
+
+	model, _ := initPlayerView("alice", nil)
+	castVote("alice", "8")
+	revealVotesAfter(revealCountdown * revealTickInterval)
+
+	got := model.View()
+	if !strings.Contains(got, "Revealing...") || strings.Contains(got, "alice: 8") {
+		t.Errorf("View() during the countdown shows the results:\n%s", got)
+	}
+
+	state.mu.Lock()
+	state.revealAt = time.Now()
+	state.mu.Unlock()
+
+	got = model.View()
+	if strings.Contains(got, "Revealing...") || !strings.Contains(got, "8") {
+		t.Errorf("View() after the countdown doesn't show the results:\n%s", got)
+	}
+}
+
+// TestVoteRecorded tests that the vote is confirmed once the game state has it,
+// and that the selection reverts when the round is cleared
+func TestVoteRecorded(t *testing.T) {
+	state = newGameState()
+
+	model, _ := initPlayerView("alice", nil)
+	if got := model.View(); strings.Contains(got, "Vote recorded") {
+		t.Errorf("View() before voting confirms a vote:\n%s", got)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	if got := model.View(); !strings.Contains(got, "✓ Vote recorded") {
+		t.Errorf("View() after voting doesn't confirm the vote:\n%s", got)
+	}
+
+	// The confirmation follows the game state before the next refresh
+	clearPlayerState()
+	if got := model.View(); strings.Contains(got, "Vote recorded") {
+		t.Errorf("View() after the clear still confirms the vote:\n%s", got)
+	}
+
+	model, _ = model.Update(tickMsg{})
+	if got := model.View(); strings.Contains(got, "Selected: 5") {
+		t.Errorf("View() after refreshing still shows the cleared vote:\n%s", got)
+	}
+}
+
+// TestIdleTimeout tests that inactive players are warned before they are
+// disconnected, and that a key press after the warning cancels the disconnect
+func TestIdleTimeout(t *testing.T) {
+	state = newGameState()
+	options.IdleTimeout = time.Minute
+	defer func() { options.IdleTimeout = 0 }()
+
+	model, _ := initPlayerView("alice", nil)
+	p := model.(playerView)
+	p.lastActive = time.Now().Add(-45 * time.Second)
+
+	if got := p.View(); !strings.Contains(got, "disconnected soon") {
+		t.Errorf("View() after 45s idle doesn't warn:\n%s", got)
+	}
+	model, cmd := p.Update(tickMsg{})
+	if cmd != nil && isQuit(cmd()) {
+		t.Fatalf("Update(tick) after 45s idle disconnected, want a warning only")
+	}
+
+	// The key press dismisses the warning without voting
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	if got := model.View(); strings.Contains(got, "disconnected soon") {
+		t.Errorf("View() after a key press still warns:\n%s", got)
+	}
+	if vote := state.players["alice"].vote(); vote.selected {
+		t.Errorf("key dismissing the warning voted %q", vote.points)
+	}
+	if _, cmd = model.Update(tickMsg{}); cmd != nil && isQuit(cmd()) {
+		t.Errorf("Update(tick) after activity disconnected")
+	}
+
+	p = model.(playerView)
+	p.lastActive = time.Now().Add(-time.Minute)
+	if _, cmd = p.Update(tickMsg{}); cmd == nil || !isQuit(cmd()) {
+		t.Errorf("Update(tick) after the idle timeout didn't disconnect")
+	}
+}
+
+// TestPlayerResize tests that the card list follows the size of the terminal,
+// keeping a few cards visible on small ones
+func TestPlayerResize(t *testing.T) {
+	state = newGameState()
+	model, _ := initPlayerView("alice", nil)
+
+	tests := []struct {
+		width, height         int
+		wantWidth, wantHeight int
+	}{
+		{120, 40, 118, 40 - listChrome},
+		{80, 24, 78, 24 - listChrome},
+		{10, 8, listWidth, minListHeight},
+	}
+
+	for _, tt := range tests {
+		model, _ = model.Update(tea.WindowSizeMsg{Width: tt.width, Height: tt.height})
+		l := model.(playerView).list
+		if l.Width() != tt.wantWidth || l.Height() != tt.wantHeight {
+			t.Errorf("list size after resize to %dx%d = %dx%d, want %dx%d",
+				tt.width, tt.height, l.Width(), l.Height(), tt.wantWidth, tt.wantHeight)
+		}
+	}
+}
+
+// TestQuitConfirmation tests that players who voted confirm quitting and
+// players who haven't quit right away
+func TestQuitConfirmation(t *testing.T) {
+	tests := []struct {
+		name        string
+		vote        string
+		keys        []string
+		wantConfirm bool
+		wantQuit    bool
+	}{
+		{"no vote quits right away", "", []string{"q"}, false, true},
+		{"vote asks first", "5", []string{"q"}, true, false},
+		{"yes quits", "5", []string{"q", "y"}, false, true},
+		{"no stays", "5", []string{"q", "n"}, false, false},
+		{"esc stays", "5", []string{"q", "esc"}, false, false},
+		{"other keys are ignored", "5", []string{"q", "3"}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state = newGameState()
+			model, _ := initPlayerView("alice", nil)
+			if tt.vote != "" {
+				model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.vote)})
+			}
+
+			var cmd tea.Cmd
+			for _, k := range tt.keys {
+				msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+				if k == "esc" {
+					msg = tea.KeyMsg{Type: tea.KeyEsc}
+				}
+				model, cmd = model.Update(msg)
+			}
+
+			p := model.(playerView)
+			if p.confirmQuit != tt.wantConfirm {
+				t.Errorf("confirmQuit = %v, want %v", p.confirmQuit, tt.wantConfirm)
+			}
+			if got := strings.Contains(p.View(), "Quit? votes will be lost (y/n)"); got != tt.wantConfirm {
+				t.Errorf("View() shows the confirmation = %v, want %v", got, tt.wantConfirm)
+			}
+
+			state.mu.RLock()
+			player, joined := state.players["alice"]
+			state.mu.RUnlock()
+			if joined == tt.wantQuit {
+				t.Errorf("player still joined = %v, want %v", joined, !tt.wantQuit)
+			}
+			if quit := cmd != nil && isQuit(cmd()); quit != tt.wantQuit {
+				t.Errorf("quit = %v, want %v", quit, tt.wantQuit)
+			}
+			if joined && tt.vote != "" && player.vote().points != tt.vote {
+				t.Errorf("vote = %q, want %q kept", player.vote().points, tt.vote)
+			}
+		})
+	}
+}
+
+// isQuit reports whether msg ends the program.
+func isQuit(msg tea.Msg) bool {
+	_, ok := msg.(tea.QuitMsg)
+	return ok
+}
+
+// TestNameLimit tests that the configured name limit sizes the name input and
+// bounds the accepted names, keeping the 20 characters of old by default
+func TestNameLimit(t *testing.T) {
+	defer func() { options.NameLimit = defaultNameLimit }()
+
+	if defaultOptions.NameLimit != 20 {
+		t.Errorf("default name limit = %d, want 20", defaultOptions.NameLimit)
+	}
+	for _, limit := range []int{defaultNameLimit, 12, 50} {
+		options.NameLimit = limit
+		v := initialNameInputView(nil)
+		if v.textInput.CharLimit != limit || v.textInput.Width != limit {
+			t.Errorf("limit %d: CharLimit = %d, Width = %d, want %d", limit, v.textInput.CharLimit, v.textInput.Width, limit)
+		}
+		if err := validatePlayerName(strings.Repeat("a", limit)); err != nil {
+			t.Errorf("limit %d: name of %d characters refused: %v", limit, limit, err)
+		}
+		if err := validatePlayerName(strings.Repeat("a", limit+1)); err == nil {
+			t.Errorf("limit %d: name of %d characters accepted", limit, limit+1)
+		}
+	}
+}
+
+// TestVoteChanges tests that changing the card counts as a change, flagged
+// with a * in the revealed list, while picking the same card again does not
+func TestVoteChanges(t *testing.T) {
+	state = newGameState()
+	options.NoSuspense = true
+	defer func() { options.NoSuspense = false }()
+
+	model, _ := initPlayerView("alice", nil)
+	initPlayerView("bob", nil)
+	model, _ = model.Update(keyRunes("5"))
+	model, _ = model.Update(keyRunes("5"))
+	castVote("bob", "3")
+
+	state.mu.RLock()
+	vote := state.players["alice"].vote()
+	state.mu.RUnlock()
+	if vote.voteChanges != 0 || vote.changedAt.IsZero() {
+		t.Errorf("after the same card twice: changes = %d, changedAt = %v, want 0 and set", vote.voteChanges, vote.changedAt)
+	}
+
+	model.Update(keyRunes("8"))
+	state.mu.RLock()
+	vote = state.players["alice"].vote()
+	state.mu.RUnlock()
+	if vote.voteChanges != 1 || vote.changedAt.Before(vote.votedAt) {
+		t.Errorf("after changing the card: changes = %d, want 1 and changedAt after votedAt", vote.voteChanges)
+	}
+
+	// Voting the same card again after retracting counts as a change too
+	initPlayerView("carol", nil)
+	castVote("carol", "5")
+	retractVote("carol")
+	castVote("carol", "5")
+	state.mu.RLock()
+	retracted := state.players["carol"].vote()
+	state.mu.RUnlock()
+	if retracted.voteChanges != 1 {
+		t.Errorf("after retracting and voting again: changes = %d, want 1", retracted.voteChanges)
+	}
+
+	revealVotes()
+	view := newMasterView().View()
+	if want := "alice: 8 * " + vote.changedAt.Format("15:04:05"); !strings.Contains(view, want) {
+		t.Errorf("master View() doesn't flag alice's changed vote with %q:\n%s", want, view)
+	}
+	if !strings.Contains(view, "carol: 5 *") {
+		t.Errorf("master View() doesn't flag carol's retracted vote:\n%s", view)
+	}
+	if strings.Contains(view, "bob: 3 *") {
+		t.Errorf("master View() flags bob's unchanged vote:\n%s", view)
+	}
+
+	clearPlayerState()
+	state.mu.RLock()
+	vote = state.players["alice"].vote()
+	state.mu.RUnlock()
+	if vote.voteChanges != 0 {
+		t.Errorf("changes after clear = %d, want 0", vote.voteChanges)
+	}
+}
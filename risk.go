@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// complete reports whether the player cast every card of the round: the effort
+// card, and the risk card too when estimating risk.
+func (v playerVote) complete() bool {
+	return v.selected && (!options.Risk || v.riskSelected)
+}
+
+// castRisk records the risk card of the named player, who must have voted
+// their effort card first. It returns false once votes are revealed, if the
+// player is unknown, or hasn't voted their effort yet.
+func castRisk(name, card string) bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	if state.masterRevealed {
+		return false
+	}
+	player, exists := state.players[playerKey(name)]
+	if !exists {
+		return false
+	}
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	if !player.selected {
+		return false
+	}
+	player.risk = card
+	player.riskSelected = true
+	return true
+}
+
+// collectDimensions returns the effort and the risk cards voted in the round.
+// Callers must hold state.mu.
+func collectDimensions() (effort, risk []string) {
+	for _, player := range state.players {
+		vote := player.vote()
+		if vote.selected {
+			effort = append(effort, vote.points)
+		}
+		if vote.riskSelected {
+			risk = append(risk, vote.risk)
+		}
+	}
+	return effort, risk
+}
+
+// showDualVotes renders the statistics of the effort and the risk cards side
+// by side, each with its average, median, and compact distribution.
+func showDualVotes(effort, risk []string) string {
+	columns := lipgloss.JoinHorizontal(lipgloss.Top,
+		dimensionView(t("stats.effort"), effort),
+		"    ",
+		dimensionView(t("stats.risk"), risk),
+	)
+	return "\n" + t("stats.title") + "\n" + columns + "\n"
+}
+
+// dimensionView renders the statistics of the cards voted for one dimension.
+func dimensionView(title string, points []string) string {
+	var s strings.Builder
+	s.WriteString(labelStyle.Render(title) + "\n")
+	if len(points) == 0 {
+		s.WriteString(t("stats.noVotes") + "\n")
+		return s.String()
+	}
+
+	avg, median, distribution := calculateStatistics(points)
+	if avg > 0 {
+		s.WriteString(t("stats.average", formatStat(avg)) + "\n")
+	}
+	s.WriteString(t("stats.median", median) + "\n")
+	if wideSpread(numericPoints(points), options.Spread) {
+		s.WriteString(spreadStyle.Render(t("stats.wideSpread")) + "\n")
+	}
+	s.WriteString(renderCompact(distribution, len(points)))
+	return s.String()
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestCollectDimensions tests aggregating the effort and risk cards separately
+func TestCollectDimensions(t *testing.T) {
+	state = newGameState()
+	options.Risk = true
+	defer func() { options.Risk = false }()
+
+	votes := []struct {
+		name, effort, risk string
+	}{
+		{"alice", "5", "8"},
+		{"bob", "3", "2"},
+		{"carol", "8", ""},
+		{"dave", "", ""},
+	}
+	for _, v := range votes {
+		addPlayer(v.name, nil)
+		if v.effort != "" {
+			castVote(v.name, v.effort)
+		}
+		if v.risk != "" {
+			castRisk(v.name, v.risk)
+		}
+	}
+
+	state.mu.RLock()
+	effort, risk := collectDimensions()
+	state.mu.RUnlock()
+	slices.Sort(effort)
+	slices.Sort(risk)
+	if want := []string{"3", "5", "8"}; !slices.Equal(effort, want) {
+		t.Errorf("effort = %v, want %v", effort, want)
+	}
+	if want := []string{"2", "8"}; !slices.Equal(risk, want) {
+		t.Errorf("risk = %v, want %v", risk, want)
+	}
+
+	got := showDualVotes(effort, risk)
+	for _, want := range []string{"Effort", "Risk", "Average: 5.3", "Average: 5.0", "3: 1 (33%)", "2: 1 (50%)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("showDualVotes() doesn't contain %q:\n%s", want, got)
+		}
+	}
+	// Both dimensions are on the same lines
+	if line := strings.Split(got, "\n")[2]; !strings.Contains(line, "Effort") || !strings.Contains(line, "Risk") {
+		t.Errorf("showDualVotes() doesn't show the dimensions side by side:\n%s", got)
+	}
+}
+
+// TestCastRisk tests that the risk card follows the effort card
+func TestCastRisk(t *testing.T) {
+	state = newGameState()
+	addPlayer("alice", nil)
+
+	if castRisk("alice", "8") {
+		t.Errorf("castRisk() before the effort vote = true, want false")
+	}
+	castVote("alice", "5")
+	if !castRisk("alice", "8") {
+		t.Errorf("castRisk() after the effort vote = false, want true")
+	}
+	revealVotes()
+	if castRisk("alice", "3") {
+		t.Errorf("castRisk() after the reveal = true, want false")
+	}
+}
+
+// TestRiskFlow tests that players vote their effort, then their risk, and
+// only count as voted with both
+func TestRiskFlow(t *testing.T) {
+	state = newGameState()
+	options.Risk, options.NoSuspense = true, true
+	defer func() { options.Risk, options.NoSuspense = false, false }()
+
+	model, _ := initPlayerView("alice", nil)
+	model, _ = model.Update(keyRunes("5"))
+	if p := model.(playerView); p.list.Title != "Select Risk" {
+		t.Errorf("list title after the effort vote = %q, want Select Risk", p.list.Title)
+	}
+	if view := newMasterView().View(); !strings.Contains(view, "Voting Progress: 0/1") {
+		t.Errorf("master View() counts the effort vote alone as voted:\n%s", view)
+	}
+
+	model, _ = model.Update(keyRunes("8"))
+	state.mu.RLock()
+	vote := state.players["alice"].vote()
+	state.mu.RUnlock()
+	if vote.points != "5" || vote.risk != "8" {
+		t.Errorf("vote = %q risk %q, want 5 risk 8", vote.points, vote.risk)
+	}
+	if view := model.View(); !strings.Contains(view, "Selected: 5") || !strings.Contains(view, "Risk: 8") {
+		t.Errorf("player View() doesn't show both cards:\n%s", view)
+	}
+
+	master := newMasterView()
+	if view := master.View(); !strings.Contains(view, "alice: ✓") || !strings.Contains(view, "Voting Progress: 1/1") {
+		t.Errorf("master View() doesn't count the complete vote:\n%s", view)
+	}
+	revealVotes()
+	if view := master.View(); !strings.Contains(view, "alice: 5 (risk 8)") || !strings.Contains(view, "Effort") {
+		t.Errorf("master View() after reveal doesn't show the risk:\n%s", view)
+	}
+}
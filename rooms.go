@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// timerKeys are the keys of the voting timer presets, shortest first.
+var timerKeys = []string{"f1", "f3", "f6"}
+
+// roomSettings are the deck and voting timer presets of a room. Settings left
+// out use the global defaults.
+type roomSettings struct {
+	Deck   []string `json:"deck"`
+	Timers []string `json:"timers"`
+}
+
+// parseRoomConfig reads the room configuration, a JSON object mapping room
+// names to their settings, like
+//
+//	{"backend": {"deck": ["1", "2", "3", "5", "8", "?"], "timers": ["30s", "1m", "2m"]}}
+//
+// Decks must be valid and timers must list one duration per timer key.
+func parseRoomConfig(r io.Reader) (map[string]roomSettings, error) {
+	var rooms map[string]roomSettings
+	if err := json.NewDecoder(r).Decode(&rooms); err != nil {
+		return nil, fmt.Errorf("failed to parse room config: %w", err)
+	}
+	for name, settings := range rooms {
+		if settings.Deck != nil {
+			if err := checkDeck(settings.Deck); err != nil {
+				return nil, fmt.Errorf("room %s: %w", name, err)
+			}
+		}
+		if settings.Timers != nil {
+			if _, err := parseTimerPresets(settings.Timers); err != nil {
+				return nil, fmt.Errorf("room %s: %w", name, err)
+			}
+		}
+	}
+	return rooms, nil
+}
+
+// loadRoomConfig reads the room configuration from the file at path.
+func loadRoomConfig(path string) (map[string]roomSettings, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open room config: %w", err)
+	}
+	defer f.Close()
+
+	return parseRoomConfig(f)
+}
+
+// parseTimerPresets parses one positive duration per timer key, like "30s".
+func parseTimerPresets(timers []string) (map[string]time.Duration, error) {
+	if len(timers) != len(timerKeys) {
+		return nil, fmt.Errorf("need %d timer presets, got %d", len(timerKeys), len(timers))
+	}
+	presets := make(map[string]time.Duration, len(timers))
+	for i, timer := range timers {
+		d, err := time.ParseDuration(timer)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timer preset %q", timer)
+		}
+		presets[timerKeys[i]] = d
+	}
+	return presets, nil
+}
+
+// settingsForRoom returns the settings of the named room, filling in the
+// global defaults for anything the room doesn't set. Unlisted rooms get the
+// defaults.
+func settingsForRoom(rooms map[string]roomSettings, name string) roomSettings {
+	settings := rooms[name]
+	if settings.Deck == nil {
+		settings.Deck = pointOptions
+	}
+	if settings.Timers == nil {
+		for _, key := range timerKeys {
+			settings.Timers = append(settings.Timers, timerDurations[key].String())
+		}
+	}
+	return settings
+}
+
+// sampleRoom is the room of the sample room config written by showdown init.
+const sampleRoom = "default"
+
+// writeSampleConfig writes a room config for the default room with the
+// default deck and timer presets, in the format parseRoomConfig reads, as a
+// template to start from.
+func writeSampleConfig(w io.Writer) error {
+	rooms := map[string]roomSettings{sampleRoom: settingsForRoom(nil, sampleRoom)}
+	data, err := json.MarshalIndent(rooms, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// sampleServerConfig returns the options of the sample config written by
+// showdown init -config, in the format of the -config file: the defaults of
+// the configuration, loading the room config at roomPath when set. The banner
+// and placeholder of the name input are left out, as their defaults follow
+// -lang, and so is the deck, whose default follows -estimate-time.
+func sampleServerConfig(roomPath string) map[string]any {
+	cfg := defaultConfig()
+	cfg.Room = sampleRoom
+	cfg.RoomConfigPath = roomPath
+
+	sample := configOptions(cfg)
+	delete(sample, "welcome")
+	delete(sample, "name-placeholder")
+	delete(sample, "deck")
+	return sample
+}
+
+// configOptions returns the fields of cfg tagged with their option name, and
+// those of its embedded options, as values of the -config file: durations as
+// text and lists as comma-separated text.
+func configOptions(cfg Config) map[string]any {
+	sample := make(map[string]any)
+	var collect func(v reflect.Value)
+	collect = func(v reflect.Value) {
+		for i := range v.NumField() {
+			field, value := v.Type().Field(i), v.Field(i)
+			if field.Anonymous {
+				collect(value)
+				continue
+			}
+			name := field.Tag.Get("json")
+			if name == "" {
+				continue
+			}
+			switch value := value.Interface().(type) {
+			case time.Duration:
+				sample[name] = value.String()
+			case []string:
+				sample[name] = strings.Join(value, ",")
+			default:
+				sample[name] = value
+			}
+		}
+	}
+	collect(reflect.ValueOf(cfg))
+	return sample
+}
+
+// writeSampleServerConfig writes the sample config of the server options, as
+// a template for the -config file.
+func writeSampleServerConfig(w io.Writer, roomPath string) error {
+	data, err := json.MarshalIndent(sampleServerConfig(roomPath), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// createSample writes a sample config to a new file at path with write,
+// refusing to overwrite an existing file.
+func createSample(path string, write func(io.Writer) error) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create config: %w", err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return f.Close()
+}
+
+// runInit runs the init subcommand: it writes the sample room config to the
+// path given as argument, refusing to overwrite an existing file, or to out
+// without one. With -config it also writes a sample config of the server
+// options to that path, loading the room config.
+func runInit(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("showdown init", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Also write a sample config of the server options for -config to this path")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("usage: showdown init [-config path] [path]")
+	}
+	roomPath := fs.Arg(0)
+
+	if roomPath == "" {
+		if err := writeSampleConfig(out); err != nil {
+			return err
+		}
+	} else if err := createSample(roomPath, writeSampleConfig); err != nil {
+		return err
+	}
+	if *configPath != "" {
+		return createSample(*configPath, func(w io.Writer) error { return writeSampleServerConfig(w, roomPath) })
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestRoomSettings tests that each room picks up its own deck and timers, and
+// unlisted rooms the defaults
+func TestRoomSettings(t *testing.T) {
+	rooms, err := parseRoomConfig(strings.NewReader(`{
+		"backend": {"deck": ["1", "2", "3", "5", "8", "13", "?"], "timers": ["30s", "1m", "2m"]},
+		"frontend": {"deck": ["XS", "S", "M", "L", "XL"]}
+	}`))
+	if err != nil {
+		t.Fatalf("parseRoomConfig() err = %v", err)
+	}
+
+	tests := []struct {
+		room       string
+		wantDeck   []string
+		wantTimers []string
+	}{
+		{"backend", []string{"1", "2", "3", "5", "8", "13", "?"}, []string{"30s", "1m", "2m"}},
+		{"frontend", []string{"XS", "S", "M", "L", "XL"}, []string{"15s", "30s", "1m0s"}},
+		{"design", pointOptions, []string{"15s", "30s", "1m0s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.room, func(t *testing.T) {
+			settings := settingsForRoom(rooms, tt.room)
+			if !slices.Equal(settings.Deck, tt.wantDeck) {
+				t.Errorf("deck = %v, want %v", settings.Deck, tt.wantDeck)
+			}
+			if !slices.Equal(settings.Timers, tt.wantTimers) {
+				t.Errorf("timers = %v, want %v", settings.Timers, tt.wantTimers)
+			}
+		})
+	}
+}
+
+// TestParseRoomConfigErrors tests rejecting invalid room configurations
+func TestParseRoomConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"invalid JSON", `{"backend": `},
+		{"duplicate card", `{"backend": {"deck": ["1", "1"]}}`},
+		{"too few timers", `{"backend": {"timers": ["30s"]}}`},
+		{"invalid timer", `{"backend": {"timers": ["30s", "soon", "2m"]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseRoomConfig(strings.NewReader(tt.config)); err == nil {
+				t.Errorf("parseRoomConfig(%s) err = nil, want error", tt.config)
+			}
+		})
+	}
+}
+
+// TestSampleConfig tests that the sample config of showdown init loads back
+// as the default deck and timers, and that init doesn't overwrite a file
+func TestSampleConfig(t *testing.T) {
+	var out strings.Builder
+	if err := runInit(nil, &out); err != nil {
+		t.Fatalf("runInit() err = %v", err)
+	}
+	rooms, err := parseRoomConfig(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatalf("sample config doesn't load: %v\n%s", err, out.String())
+	}
+	if got, want := rooms[sampleRoom], settingsForRoom(nil, sampleRoom); !slices.Equal(got.Deck, want.Deck) || !slices.Equal(got.Timers, want.Timers) {
+		t.Errorf("sample room = %+v, want %+v", got, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "rooms.json")
+	if err := runInit([]string{path}, nil); err != nil {
+		t.Fatalf("runInit(%q) err = %v", path, err)
+	}
+	if _, err := loadRoomConfig(path); err != nil {
+		t.Errorf("written sample config doesn't load: %v", err)
+	}
+	if err := runInit([]string{path}, nil); err == nil {
+		t.Errorf("runInit() over an existing file err = nil, want error")
+	}
+
+	// The sample server config loads the sample room config it's written with
+	dir := t.TempDir()
+	roomPath, configPath := filepath.Join(dir, "rooms.json"), filepath.Join(dir, "showdown.json")
+	if err := runInit([]string{"-config", configPath, roomPath}, nil); err != nil {
+		t.Fatalf("runInit(-config) err = %v", err)
+	}
+	file, err := loadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("loadConfigFile() of the sample server config err = %v", err)
+	}
+	for _, name := range []string{"port", "max-players", "idle-timeout", "reserved", "room-config"} {
+		if _, ok := file[name]; !ok {
+			t.Errorf("sample server config lacks %q:\n%v", name, file)
+		}
+	}
+	cfg, err := parseConfig([]string{"-config", configPath, "-addr", "127.0.0.1", "-host-key", filepath.Join(dir, "showdown_ed25519")})
+	if err != nil {
+		t.Fatalf("sample server config doesn't load: %v", err)
+	}
+	if want := fmt.Sprintf("127.0.0.1:%d", defaultPort); cfg.Addr != want || cfg.Room != sampleRoom {
+		t.Errorf("sample server config address = %q, room = %q, want %q, %q", cfg.Addr, cfg.Room, want, sampleRoom)
+	}
+	if !slices.Equal(cfg.Deck, settingsForRoom(nil, sampleRoom).Deck) {
+		t.Errorf("sample server config deck = %v, want the sample room's", cfg.Deck)
+	}
+	if !reflect.DeepEqual(cfg.Options, defaultOptions) {
+		t.Errorf("sample server config options = %+v, want the defaults %+v", cfg.Options, defaultOptions)
+	}
+}
+
+// TestSampleServerConfigEstimateTime tests that turning on estimate-time in
+// the sample server config switches to the time deck
+func TestSampleServerConfigEstimateTime(t *testing.T) {
+	sample := sampleServerConfig("")
+	sample["estimate-time"] = true
+	data, err := json.Marshal(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "showdown.json")
+	if err := os.WriteFile(configPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := parseConfig([]string{"-config", configPath, "-host-key", filepath.Join(dir, "showdown_ed25519")})
+	if err != nil {
+		t.Fatalf("sample server config with estimate-time doesn't load: %v", err)
+	}
+	if !slices.Equal(cfg.Deck, timeDeck) {
+		t.Errorf("deck = %v, want the time deck %v", cfg.Deck, timeDeck)
+	}
+}
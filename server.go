@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/wish/logging"
+)
+
+// Exit codes of the server, for supervisors telling a clean shutdown apart
+// from failures that a restart won't fix.
+const (
+	exitOK      = 0 // clean shutdown on a signal
+	exitStartup = 1 // failure to start or keep serving, like a port in use
+	exitConfig  = 2 // invalid flags or configuration files
+)
+
+// fatal logs msg as an error with its key-value pairs and exits with code.
+func fatal(code int, msg string, keyvals ...any) {
+	log.Error(msg, keyvals...)
+	os.Exit(code)
+}
+
+// serverMiddleware returns the middleware of the SSH server sessions, in the
+// order Wish runs them: the last one first.
+func serverMiddleware(cfg Config) []wish.Middleware {
+	return []wish.Middleware{
+		connectionLimitMiddleware(cfg.MaxConnections, cfg.MaxConnectionsPerIP),
+		sessionTimeoutMiddleware(),
+		bubbletea.Middleware(pokerHandler(cfg)),
+		banMiddleware(),
+		logging.Middleware(),
+		sessionCloseMiddleware(),
+		syncSessionMiddleware(),
+	}
+}
+
+// newServer creates the SSH server listening on the configured address with
+// the host keys, the authentication of the players and the Scrum Master, and
+// the session middleware. Connection limits left at zero get their defaults.
+// It doesn't bind the address yet.
+func newServer(cfg Config) (*ssh.Server, error) {
+	if cfg.MaxConnections == 0 {
+		cfg.MaxConnections = defaultMaxConnections
+	}
+	if cfg.MaxConnectionsPerIP == 0 {
+		cfg.MaxConnectionsPerIP = defaultMaxConnectionsPerIP
+	}
+	opts := []ssh.Option{wish.WithAddress(cfg.Addr)}
+	for _, path := range cfg.HostKeys {
+		opts = append(opts, wish.WithHostKeyPath(path))
+	}
+	opts = append(opts, authOptions(cfg.MasterPassword, cfg.RequireKey)...)
+	opts = append(opts, wish.WithMiddleware(serverMiddleware(cfg)...))
+	return wish.NewServer(opts...)
+}
+
+// startSSH binds the address of the SSH server and serves it in the
+// background, marking the server ready. The returned channel receives the
+// error that stopped serving. Binding fails right away, for example on a port
+// in use. The address of the server becomes the bound one, resolving port 0.
+func startSSH(s *ssh.Server) (<-chan error, error) {
+	l, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return nil, err
+	}
+	s.Addr = l.Addr().String()
+	sshReady.Store(true)
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- s.Serve(l) }()
+	return serveErr, nil
+}
+
+// startHTTP binds addr and serves handler on it in the background, logging a
+// failure to serve under name. Binding fails right away, like startSSH.
+func startHTTP(name, addr string, handler http.Handler) (*http.Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", name, err)
+	}
+	server := &http.Server{
+		Addr:              l.Addr().String(),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Info("Starting "+name, "address", server.Addr)
+	go func() {
+		if err := server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Could not serve "+name, "error", err)
+		}
+	}()
+	return server, nil
+}
+
+// loadConfigFiles loads the authorization files of the configuration: it
+// checks the authorized keys file can be read, and loads the Scrum Master
+// names, the facilitators, and the ban list.
+func loadConfigFiles(cfg Config) error {
+	// Fail fast on an authorized keys file that can't be read, instead of
+	// denying the Scrum Master on every connection
+	if err := validateAuthorizedKeysPath(cfg.KeysPath); err != nil {
+		return fmt.Errorf("invalid authorized keys: %w", err)
+	}
+
+	if cfg.MasterNamesPath != "" {
+		if err := loadMasterNames(cfg.MasterNamesPath); err != nil {
+			return err
+		}
+	}
+	if cfg.FacilitatorsPath != "" {
+		if err := loadFacilitators(cfg.FacilitatorsPath); err != nil {
+			return err
+		}
+	}
+	if cfg.BanlistPath != "" {
+		if err := bans.load(cfg.BanlistPath); err != nil {
+			return fmt.Errorf("failed to load ban list: %w", err)
+		}
+	}
+	return nil
+}
+
+// run imports the backlog, restores the game state, and serves the SSH server
+// and the enabled HTTP endpoints until SIGINT or SIGTERM, then shuts down
+// gracefully. It returns an error when the server fails to start, or stops
+// serving unexpectedly.
+func run(cfg Config) error {
+	if cfg.jira != nil {
+		stories, err := cfg.jira.searchStories(cfg.jiraJQL)
+		if err != nil {
+			return fmt.Errorf("failed to import backlog from Jira: %w", err)
+		}
+		setBacklog(stories)
+		log.Info("Imported backlog from Jira", "stories", len(stories))
+		if cfg.jiraWrite {
+			estimateWriters = append(estimateWriters, cfg.jira)
+		}
+	}
+	if cfg.github != nil {
+		stories, err := cfg.github.openIssues(cfg.githubLabel)
+		if err != nil {
+			return fmt.Errorf("failed to import backlog from GitHub: %w", err)
+		}
+		setBacklog(stories)
+		log.Info("Imported backlog from GitHub", "stories", len(stories))
+		if cfg.githubComment {
+			estimateWriters = append(estimateWriters, cfg.github)
+		}
+	}
+
+	// Reload the ban list on SIGHUP
+	if cfg.BanlistPath != "" {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			for range hup {
+				if err := bans.load(cfg.BanlistPath); err != nil {
+					log.Error("failed to reload ban list", "error", err)
+					continue
+				}
+				log.Info("Reloaded ban list", "path", cfg.BanlistPath)
+			}
+		}()
+	}
+
+	// Resume the previous meeting from the state file when enabled
+	persistDone := make(chan struct{})
+	stopPersisting := sync.OnceFunc(func() { close(persistDone) })
+	defer stopPersisting()
+	if cfg.StateFile != "" {
+		if err := loadStateFile(cfg.StateFile); err != nil {
+			return fmt.Errorf("failed to restore game state from %s: %w", cfg.StateFile, err)
+		}
+		go persistState(cfg.StateFile, persistDone)
+	}
+	if cfg.EventsPath != "" {
+		var err error
+		if events, err = openEventLog(cfg.EventsPath, cfg.Room); err != nil {
+			return fmt.Errorf("failed to open events file: %w", err)
+		}
+		go events.flushEvents(persistDone)
+	}
+
+	// Generate missing host keys, so first starts work without setup
+	for _, path := range cfg.HostKeys {
+		fingerprint, err := ensureHostKey(path)
+		if err != nil {
+			return fmt.Errorf("failed to generate host key %s: %w", path, err)
+		}
+		if fingerprint == "" {
+			continue
+		}
+		log.Info("Generated new host key", "path", path, "fingerprint", fingerprint)
+		if key, err := readHostKey(path); err == nil {
+			fmt.Println(fingerprintBanner(key))
+		}
+	}
+
+	// Create the SSH server and bind before serving, so a port in use fails
+	// the start and readiness is only reported once connections are accepted
+	s, err := newServer(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+	logHostKeys(cfg.HostKeys)
+	log.Info("Starting Showdown server", "address", cfg.Addr, "version", serverVersion)
+	serveErr, err := startSSH(s)
+	if err != nil {
+		return err
+	}
+
+	// Start the web gateway, the state API, and the health endpoints when
+	// enabled
+	endpoints := []struct {
+		name, addr string
+		handler    func() http.Handler
+	}{
+		{"web gateway", cfg.HTTPAddr, func() http.Handler { return newWebHandler(cfg.MaxConnections, cfg.MaxConnectionsPerIP) }},
+		{"state API", cfg.APIAddr, newAPIHandler},
+		{"health endpoints", cfg.HealthAddr, newHealthHandler},
+	}
+	var httpServers []*http.Server
+	for _, endpoint := range endpoints {
+		if endpoint.addr == "" {
+			continue
+		}
+		server, err := startHTTP(endpoint.name, endpoint.addr, endpoint.handler())
+		if err != nil {
+			// A configured endpoint that can't bind fails the start
+			sshReady.Store(false)
+			s.Close()
+			for _, server := range httpServers {
+				server.Close()
+			}
+			return err
+		}
+		httpServers = append(httpServers, server)
+	}
+
+	// Serve until stopped by a signal or a failing server
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	var runErr error
+	select {
+	case <-stop:
+	case err := <-serveErr:
+		runErr = fmt.Errorf("server stopped unexpectedly: %w", err)
+	}
+	log.Info("Stopping Showdown server")
+	sshReady.Store(false)
+
+	stopPersisting()
+	if cfg.StateFile != "" {
+		if err := saveStateFile(cfg.StateFile); err != nil {
+			log.Error("Could not save game state", "error", err, "path", cfg.StateFile)
+		}
+	}
+	// Reset terminal for all active sessions before shutdown
+	resetSessions()
+
+	state.mu.RLock()
+	fmt.Println(sessionSummary(state.history, state.roundsPlayed, len(state.joined)))
+	state.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+		log.Error("Could not stop server", "error", err)
+	}
+	for _, server := range httpServers {
+		if err := server.Shutdown(ctx); err != nil {
+			log.Error("Could not stop HTTP server", "error", err, "address", server.Addr)
+		}
+	}
+
+	// Close the events file last, keeping the events of the closing sessions
+	if events != nil {
+		if err := events.Close(); err != nil {
+			log.Error("Could not write events", "error", err, "path", cfg.EventsPath)
+		}
+	}
+	return runErr
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// testConfig returns the configuration of a server on a random local port
+// with a fresh host key.
+func testConfig(t *testing.T) Config {
+	t.Helper()
+	hostKey := filepath.Join(t.TempDir(), "showdown_ed25519")
+	if _, err := ensureHostKey(hostKey); err != nil {
+		t.Fatalf("ensureHostKey() err = %v", err)
+	}
+	return Config{Addr: "127.0.0.1:0", HostKeys: []string{hostKey}}
+}
+
+// TestStartSSH tests that the SSH server fails to start on a port in use,
+// without reporting ready, and serves until shut down otherwise
+func TestStartSSH(t *testing.T) {
+	defer sshReady.Store(false)
+	cfg := testConfig(t)
+
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to take a port: %v", err)
+	}
+	defer taken.Close()
+
+	busy := cfg
+	busy.Addr = taken.Addr().String()
+	s, err := newServer(busy)
+	if err != nil {
+		t.Fatalf("newServer() err = %v", err)
+	}
+	if _, err := startSSH(s); err == nil {
+		t.Errorf("startSSH() on a port in use err = nil, want an error")
+	}
+	if sshReady.Load() {
+		t.Errorf("server ready after failing to bind")
+	}
+
+	s, err = newServer(cfg)
+	if err != nil {
+		t.Fatalf("newServer() err = %v", err)
+	}
+	serveErr, err := startSSH(s)
+	if err != nil {
+		t.Fatalf("startSSH() err = %v", err)
+	}
+	if !sshReady.Load() {
+		t.Errorf("server not ready after binding")
+	}
+
+	// Wait for the version banner of the server, so it serves the listener
+	// it closes
+	conn, err := net.DialTimeout("tcp", s.Addr, time.Second)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+		t.Fatalf("failed to read the server version: %v", err)
+	}
+	conn.Close()
+	s.Close()
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, ssh.ErrServerClosed) {
+			t.Errorf("serve err = %v, want %v", err, ssh.ErrServerClosed)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("server still serving after close")
+	}
+}
+
+// TestRunHTTPPortInUse tests that the server fails to start when a configured
+// HTTP endpoint can't bind, rather than running without it
+func TestRunHTTPPortInUse(t *testing.T) {
+	defer sshReady.Store(false)
+	state = newGameState()
+
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to take a port: %v", err)
+	}
+	defer taken.Close()
+
+	cfg := testConfig(t)
+	cfg.HealthAddr = taken.Addr().String()
+	done := make(chan error, 1)
+	go func() { done <- run(cfg) }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "health endpoints") {
+			t.Errorf("run() err = %v, want a failure to start the health endpoints", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run() still serving without the health endpoints")
+	}
+	if sshReady.Load() {
+		t.Errorf("server ready after failing to start")
+	}
+}
+
+// TestNewServer tests that a player's key is accepted and a one-shot vote
+// command goes through the middleware to the game
+func TestNewServer(t *testing.T) {
+	defer sshReady.Store(false)
+	state = newGameState()
+
+	s, err := newServer(testConfig(t))
+	if err != nil {
+		t.Fatalf("newServer() err = %v", err)
+	}
+	serveErr, err := startSSH(s)
+	if err != nil {
+		t.Fatalf("startSSH() err = %v", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signer, err := gossh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	client, err := gossh.Dial("tcp", s.Addr, &gossh.ClientConfig{
+		User:            "alice",
+		Auth:            []gossh.AuthMethod{gossh.PublicKeys(signer)},
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		t.Fatalf("failed to open session: %v", err)
+	}
+	out, err := session.Output("vote 5")
+	if err != nil {
+		t.Fatalf("vote command err = %v, output %q", err, out)
+	}
+	if want := "Voted 5 as alice\n"; string(out) != want {
+		t.Errorf("vote command output = %q, want %q", out, want)
+	}
+
+	// Shut down once the session is over, so its handler is done with the
+	// game state before the next test replaces it
+	client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for !playerOffline("alice") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := s.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown() err = %v", err)
+	}
+	if err := <-serveErr; !errors.Is(err, ssh.ErrServerClosed) {
+		t.Errorf("serve err = %v, want %v", err, ssh.ErrServerClosed)
+	}
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	player, ok := state.players["alice"]
+	if !ok {
+		t.Fatalf("alice didn't join")
+	}
+	if vote := player.vote(); vote.points != "5" {
+		t.Errorf("alice's vote = %q, want 5", vote.points)
+	}
+	if !player.offline {
+		t.Errorf("alice still online after the command")
+	}
+}
+
+// playerOffline reports whether the named player's session has ended.
+func playerOffline(name string) bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	player, ok := state.players[name]
+	return ok && player.offline
+}
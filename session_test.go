@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scriptedJoin joins a player through the name input view in a scripted session.
+type scriptedJoin struct {
+	name string
+}
+
+// scriptedPlayerMsg delivers a message to a joined player's view in a scripted session.
+type scriptedPlayerMsg struct {
+	name string
+	msg  tea.Msg
+}
+
+// runScriptedSession drives a master view and player views on a fresh game
+// state without an SSH server. Messages are sent to the master view unless
+// wrapped in scriptedJoin or scriptedPlayerMsg. Commands returned by Update
+// are dropped, so timers and ticks never fire, and votes are revealed without
+// the countdown. It returns the master view rendered after each input.
+func runScriptedSession(inputs []tea.Msg) []string {
+	defer func(suspense bool) { options.NoSuspense = suspense }(options.NoSuspense)
+	options.NoSuspense = true
+	state = newGameState()
+
+	var master tea.Model = newMasterView()
+	players := make(map[string]tea.Model)
+	frames := make([]string, 0, len(inputs))
+
+	for _, input := range inputs {
+		switch input := input.(type) {
+		case scriptedJoin:
+			v := initialNameInputView(nil)
+			v.textInput.SetValue(input.name)
+			players[input.name], _ = v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		case scriptedPlayerMsg:
+			players[input.name], _ = players[input.name].Update(input.msg)
+		default:
+			master, _ = master.Update(input)
+		}
+		frames = append(frames, master.View())
+	}
+
+	return frames
+}
+
+// keyRunes returns the key message for typing the given characters.
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// TestScriptedSession tests a full round of join, vote, reveal, and clear
+func TestScriptedSession(t *testing.T) {
+	frames := runScriptedSession([]tea.Msg{
+		scriptedJoin{name: "alice"},
+		scriptedJoin{name: "bob"},
+		scriptedPlayerMsg{name: "alice", msg: tea.KeyMsg{Type: tea.KeyEnter}},
+		scriptedPlayerMsg{name: "bob", msg: tea.KeyMsg{Type: tea.KeyDown}},
+		scriptedPlayerMsg{name: "bob", msg: tea.KeyMsg{Type: tea.KeyEnter}},
+		keyRunes("r"),
+		keyRunes("c"),
+	})
+
+	tests := []struct {
+		name       string
+		frame      int
+		wantSubstr []string
+	}{
+		{"players joined", 1, []string{"Connected Players: 2", "alice: waiting...", "bob: waiting..."}},
+		{"alice voted", 2, []string{"alice: ✓", "bob: waiting...", "Voting Progress: 1/2"}},
+		{"bob voted", 4, []string{"alice: ✓", "bob: ✓", "Voting Progress: 2/2"}},
+		{"votes revealed", 5, []string{"alice: 0.5", "bob: 1", "Voting Statistics", "Median: 0.8"}},
+		{"round cleared", 6, []string{"Round 2.1", "alice: waiting...", "Voting Progress: 0/2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, substr := range tt.wantSubstr {
+				if !strings.Contains(frames[tt.frame], substr) {
+					t.Errorf("frame %d missing substring %q\nGot: %s", tt.frame, substr, frames[tt.frame])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+)
+
+// Theme holds the configurable colors of the user interface.
+type Theme struct {
+	// GradientStart and GradientEnd are the colors of the distribution bars,
+	// scaled from the start to the end of each bar.
+	GradientStart string
+	GradientEnd   string
+}
+
+// defaultTheme matches the Catppuccin Mocha colors of the rest of the UI.
+var defaultTheme = Theme{
+	GradientStart: catppuccinMaroon,
+	GradientEnd:   catppuccinLavender,
+}
+
+// theme is the selected theme, the default one unless overridden with
+// -gradient.
+var theme = defaultTheme
+
+// withScaledGradient creates the gradient option of the distribution bars.
+// It's a variable so tests can check the colors passed in.
+var withScaledGradient = progress.WithScaledGradient
+
+// hexColorRegex matches a hex color like #cba6f7.
+var hexColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// parseGradient parses the -gradient flag, two hex colors separated by a comma
+// like "#eba0ac,#b4befe", into the start and end of the gradient.
+func parseGradient(value string) (start, end string, err error) {
+	start, end, ok := strings.Cut(value, ",")
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if !ok || !hexColorRegex.MatchString(start) || !hexColorRegex.MatchString(end) {
+		return "", "", fmt.Errorf("gradient %q is not two hex colors like #eba0ac,#b4befe", value)
+	}
+	return start, end, nil
+}
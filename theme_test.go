@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/progress"
+)
+
+// TestParseGradient tests parsing the -gradient flag
+func TestParseGradient(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{"#eba0ac,#b4befe", "#eba0ac", "#b4befe", false},
+		{" #A6E3A1 , #94e2d5 ", "#A6E3A1", "#94e2d5", false},
+		{"#eba0ac", "", "", true},
+		{"red,blue", "", "", true},
+		{"#eba0ac,#b4bef", "", "", true},
+		{"", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			start, end, err := parseGradient(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGradient(%q) err = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("parseGradient(%q) = %q, %q, want %q, %q", tt.value, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+// TestGradientFromTheme tests that the distribution bars use the colors of
+// the configured theme
+func TestGradientFromTheme(t *testing.T) {
+	defer func(saved Theme) { theme = saved }(theme)
+	defer func(saved func(string, string) progress.Option) { withScaledGradient = saved }(withScaledGradient)
+
+	var gotStart, gotEnd string
+	withScaledGradient = func(start, end string) progress.Option {
+		gotStart, gotEnd = start, end
+		return progress.WithScaledGradient(start, end)
+	}
+
+	showFinalVotes([]string{"3"}, 1, 1)
+	if gotStart != catppuccinMaroon || gotEnd != catppuccinLavender {
+		t.Errorf("default gradient = %q, %q, want %q, %q", gotStart, gotEnd, catppuccinMaroon, catppuccinLavender)
+	}
+
+	theme = Theme{GradientStart: "#a6e3a1", GradientEnd: "#94e2d5"}
+	showFinalVotes([]string{"3"}, 1, 1)
+	if gotStart != "#a6e3a1" || gotEnd != "#94e2d5" {
+		t.Errorf("configured gradient = %q, %q, want #a6e3a1, #94e2d5", gotStart, gotEnd)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// workdayHours is how many hours a day of estimated work has.
+const workdayHours = 8
+
+// timeDeck is the deck of time estimates, used with -estimate-time unless
+// another deck is picked.
+var timeDeck = []string{"1h", "2h", "4h", "1d", "2d", "?"}
+
+// parseEstimate parses a time estimate in hours ("4h"), or in days of
+// workdayHours ("1.5d"), into hours. A number without a unit is taken as
+// hours. Unlike time.ParseDuration it knows days, and it takes a single unit
+// only.
+func parseEstimate(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	factor := 1.0
+	switch {
+	case strings.HasSuffix(s, "h"):
+		s = strings.TrimSuffix(s, "h")
+	case strings.HasSuffix(s, "d"):
+		s = strings.TrimSuffix(s, "d")
+		factor = workdayHours
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, false
+	}
+	return value * factor, true
+}
+
+// cardValue returns the numeric value of a card for the statistics: its
+// points, or its hours with -estimate-time. Cards like "?" have none.
+func cardValue(card string) (float64, bool) {
+	if options.EstimateTime {
+		return parseEstimate(card)
+	}
+	value, err := strconv.ParseFloat(card, 64)
+	return value, err == nil
+}
+
+// formatStat formats a statistic like the average with the configured
+// decimals, or as hours like "6h" with -estimate-time, leaving out trailing
+// zeros.
+func formatStat(value float64) string {
+	if !options.EstimateTime {
+		return fmt.Sprintf("%.*f", options.Precision, value)
+	}
+	scale := math.Pow(10, float64(options.Precision))
+	return strconv.FormatFloat(math.Round(value*scale)/scale, 'f', -1, 64) + "h"
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseEstimate tests parsing time estimates in hours and days
+func TestParseEstimate(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   float64
+		wantOK bool
+	}{
+		{"1h", 1, true},
+		{"4h", 4, true},
+		{"1d", workdayHours, true},
+		{"1.5d", 1.5 * workdayHours, true},
+		{"3", 3, true},
+		{" 2d ", 2 * workdayHours, true},
+		{"?", 0, false},
+		{"h", 0, false},
+		{"1w", 0, false},
+		{"-1h", 0, false},
+		{"1h30m", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := parseEstimate(tt.input)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseEstimate(%q) = %v, %v, want %v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestTimeEstimateStatistics tests averaging time estimates across mixed units
+// in hours
+func TestTimeEstimateStatistics(t *testing.T) {
+	options.EstimateTime = true
+	defer func() { options.EstimateTime = false }()
+
+	points := []string{"2h", "4h", "1d", "1d", "?"}
+	avg, median, _ := calculateStatistics(points)
+	if avg != 5.5 {
+		t.Errorf("average = %v, want 5.5", avg)
+	}
+	if median != "6h" {
+		t.Errorf("median = %q, want 6h", median)
+	}
+	if got := formatStat(avg); got != "5.5h" {
+		t.Errorf("formatStat(%v) = %q, want 5.5h", avg, got)
+	}
+	if got := nearestCard(avg, timeDeck); got != "4h" {
+		t.Errorf("nearestCard(%v) = %q, want 4h", avg, got)
+	}
+
+	distribution := map[string]int{"1d": 2, "2h": 1, "?": 1, "4h": 1}
+	if got := strings.Join(sortedPointValues(distribution), " "); got != "2h 4h 1d ?" {
+		t.Errorf("sortedPointValues() = %q, want %q", got, "2h 4h 1d ?")
+	}
+
+	if got := showFinalVotes([]string{"4h", "1d"}, 2, 2); !strings.Contains(got, "Average: 6h\n") {
+		t.Errorf("showFinalVotes() doesn't show the average in hours:\n%s", got)
+	}
+}
@@ -0,0 +1,483 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// websocketGUID is the fixed GUID from RFC 6455 used to compute the
+	// Sec-WebSocket-Accept handshake header.
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// maxWebMessageSize limits the payload of a single frame sent by a browser.
+	maxWebMessageSize = 4096
+
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+)
+
+// webMessage is a message sent by a browser participant over the WebSocket.
+// Type is either "join" (with Name) or "vote" (with Points).
+type webMessage struct {
+	Type   string `json:"type"`
+	Name   string `json:"name,omitempty"`
+	Points string `json:"points,omitempty"`
+}
+
+// webPlayer is a player entry in the state pushed to browser participants.
+// Points are only filled in once the votes are revealed.
+type webPlayer struct {
+	Name   string `json:"name"`
+	Voted  bool   `json:"voted"`
+	Points string `json:"points,omitempty"`
+}
+
+// webState is the snapshot of the game pushed to browser participants every
+// second, mirroring what an SSH player sees: voting closes once the votes are
+// revealed, and with -blind-reveal they're only shared later. Countdown is the
+// number of suspense ticks left before shared votes are shown.
+type webState struct {
+	Type      string      `json:"type"`
+	Name      string      `json:"name,omitempty"`
+	Selected  string      `json:"selected,omitempty"`
+	Revealed  bool        `json:"revealed"`
+	Shared    bool        `json:"shared"`
+	Countdown int         `json:"countdown,omitempty"`
+	Options   []string    `json:"options"`
+	Players   []webPlayer `json:"players"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// webClient tracks the player joined through a single WebSocket connection.
+type webClient struct {
+	name   string
+	player *playerState
+}
+
+// handleWebMessage applies a message from a browser participant to the shared
+// game state, using the same join and vote rules as SSH players.
+func handleWebMessage(c *webClient, msg webMessage) error {
+	switch msg.Type {
+	case "join":
+		if c.player != nil {
+			return errors.New("already joined")
+		}
+		name := strings.TrimSpace(msg.Name)
+		if err := checkJoin(name, nil); err != nil {
+			return err
+		}
+		c.name = name
+		c.player = addPlayer(name, nil)
+	case "vote":
+		if c.player == nil {
+			return errors.New("join before voting")
+		}
+		if !slices.Contains(currentDeck(), msg.Points) {
+			return fmt.Errorf("invalid points %q", msg.Points)
+		}
+		if !castVote(c.name, msg.Points) {
+			return errors.New("voting closed")
+		}
+	default:
+		return fmt.Errorf("unknown message type %q", msg.Type)
+	}
+	return nil
+}
+
+// leave removes the client's player from the game state, unless it was
+// already removed or replaced by the Scrum Master in the meantime.
+func (c *webClient) leave() {
+	if c.player == nil {
+		return
+	}
+	state.mu.Lock()
+	if state.players[playerKey(c.name)] == c.player {
+		removePlayer(playerKey(c.name))
+	}
+	state.mu.Unlock()
+}
+
+// active reports whether the client's player is still part of the game. It
+// returns false once the Scrum Master has disconnected all players.
+func (c *webClient) active() bool {
+	if c.player == nil {
+		return true
+	}
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return state.players[playerKey(c.name)] == c.player
+}
+
+// snapshot builds the state pushed to the client, hiding votes until reveal.
+func (c *webClient) snapshot() webState {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	now := time.Now()
+	ws := webState{
+		Type:     "state",
+		Name:     c.name,
+		Revealed: state.masterRevealed,
+		Shared:   resultsShared(now),
+		Options:  activeDeck(),
+		Players:  publicPlayers(),
+	}
+	if state.playersRevealed {
+		ws.Countdown = countdownLeft(now)
+	}
+	if c.player != nil {
+		if vote := c.player.vote(); vote.selected {
+			ws.Selected = vote.points
+		}
+	}
+
+	return ws
+}
+
+// wsConn is a minimal server side WebSocket connection supporting unfragmented
+// text frames, enough for the small JSON messages of the web gateway.
+type wsConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	mu   sync.Mutex
+}
+
+// websocketAccept computes the Sec-WebSocket-Accept header for a client key.
+func websocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// upgradeWebsocket performs the WebSocket handshake and takes over the
+// underlying connection of the request.
+func upgradeWebsocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(w, "expected websocket upgrade", http.StatusBadRequest)
+		return nil, errors.New("not a websocket request")
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("response does not support hijacking")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hijack connection: %w", err)
+	}
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", websocketAccept(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to complete handshake: %w", err)
+	}
+
+	return &wsConn{conn: conn, r: rw.Reader}, nil
+}
+
+// readFrame reads a single frame sent by the client and returns its opcode
+// and unmasked payload. Frames sent by clients must be masked.
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	if header[0]&0x80 == 0 {
+		return 0, nil, errors.New("fragmented frames are not supported")
+	}
+	opcode := header[0] & 0x0f
+	// Browsers mask every frame, so an unmasked one isn't from a browser
+	if header[1]&0x80 == 0 {
+		return 0, nil, errors.New("unmasked client frame")
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxWebMessageSize {
+		return 0, nil, fmt.Errorf("frame of %d bytes exceeds limit", length)
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return opcode, payload, nil
+}
+
+// writeFrame writes a single unmasked frame, as sent by a server.
+func writeFrame(w io.Writer, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xffff:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// send writes a frame to the connection, serializing concurrent writers.
+func (c *wsConn) send(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return writeFrame(c.conn, opcode, payload)
+}
+
+// sendJSON encodes v and sends it as a text frame.
+func (c *wsConn) sendJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.send(wsOpText, data)
+}
+
+// serveWebsocket upgrades the request and runs the gateway for a single
+// browser participant until the connection closes or the player is removed.
+func serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgradeWebsocket(w, r)
+	if err != nil {
+		log.Error("websocket upgrade failed", "error", err, "remote", r.RemoteAddr)
+		return
+	}
+	defer ws.conn.Close()
+
+	client := &webClient{}
+	defer client.leave()
+
+	// Push the state every second, like the tick of the SSH views
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if !client.active() {
+					ws.send(wsOpClose, nil)
+					ws.conn.Close()
+					return
+				}
+				if err := ws.sendJSON(client.snapshot()); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		opcode, payload, err := readFrame(ws.r)
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case wsOpClose:
+			ws.send(wsOpClose, nil)
+			return
+		case wsOpPing:
+			ws.send(wsOpPong, payload)
+			continue
+		case wsOpText:
+		default:
+			continue
+		}
+
+		var msg webMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			ws.sendJSON(webState{Type: "error", Error: "invalid message"})
+			continue
+		}
+
+		snapshot := webState{Type: "state"}
+		if err := handleWebMessage(client, msg); err != nil {
+			snapshot.Type = "error"
+			snapshot.Error = err.Error()
+		} else {
+			snapshot = client.snapshot()
+		}
+		if err := ws.sendJSON(snapshot); err != nil {
+			return
+		}
+	}
+}
+
+// webAddr is the remote address of an HTTP request, like "192.0.2.1:1234".
+type webAddr string
+
+// Network returns the network of the address. It's part of the net.Addr
+// interface.
+func (a webAddr) Network() string { return "tcp" }
+
+// String returns the address. It's part of the net.Addr interface.
+func (a webAddr) String() string { return string(a) }
+
+// sameOrigin reports whether the request comes from a page served by this
+// host, so pages of other sites can't join or vote through the browser of a
+// participant. Requests without an Origin don't come from a browser page.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// newWebHandler returns the HTTP handler of the web gateway, serving the
+// browser UI on / and the WebSocket endpoint on /ws. WebSocket connections
+// are refused from other origins and banned addresses, and count against the
+// connection limits of the SSH server.
+func newWebHandler(maxConnections, maxConnectionsPerIP int) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		if !sameOrigin(r) {
+			http.Error(w, "cross-origin request", http.StatusForbidden)
+			return
+		}
+		addr := webAddr(r.RemoteAddr)
+		if bans.bannedAddr(addr) {
+			log.Warn("Refused banned connection", "remote", r.RemoteAddr)
+			http.Error(w, "access denied", http.StatusForbidden)
+			return
+		}
+		release, err := acquireConnection(addr, maxConnections, maxConnectionsPerIP)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		defer release()
+
+		serveWebsocket(w, r)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, webPage, options.NameLimit)
+	})
+	return mux
+}
+
+// webPage is the minimal browser UI for participants without an SSH client,
+// formatted with the name limit.
+const webPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Showdown</title>
+<style>
+body { background: #1e1e2e; color: #cdd6f4; font-family: monospace; padding: 1em; }
+button { background: #313244; color: #cdd6f4; border: 1px solid #cba6f7; margin: 0.2em; padding: 0.5em 1em; font-family: monospace; }
+button.selected { background: #cba6f7; color: #11111b; }
+input { background: #313244; color: #cdd6f4; border: 1px solid #cba6f7; padding: 0.5em; font-family: monospace; }
+#error { color: #f38ba8; }
+</style>
+</head>
+<body>
+<h2>🎲 Showdown</h2>
+<div id="join">
+<input id="name" placeholder="Enter your name" maxlength="%d">
+<button onclick="join()">Join</button>
+</div>
+<div id="game" hidden>
+<p id="status"></p>
+<div id="cards"></div>
+<h3>Players</h3>
+<ul id="players"></ul>
+</div>
+<p id="error"></p>
+<script>
+const ws = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + "/ws");
+const $ = (id) => document.getElementById(id);
+function send(msg) { ws.send(JSON.stringify(msg)); }
+function join() { send({type: "join", name: $("name").value}); }
+ws.onclose = () => { $("error").textContent = "Disconnected"; };
+ws.onmessage = (event) => {
+	const msg = JSON.parse(event.data);
+	if (msg.type === "error") { $("error").textContent = msg.error; return; }
+	$("error").textContent = "";
+	if (!msg.name) return;
+	$("join").hidden = true;
+	$("game").hidden = false;
+	$("status").textContent = "Player: " + msg.name + (msg.revealed ? " (voting closed)" : "") +
+		(msg.countdown ? " Revealing..." : msg.revealed && !msg.shared ? " Waiting for the Scrum Master to share the results" : "");
+	$("cards").innerHTML = "";
+	for (const option of msg.options) {
+		const b = document.createElement("button");
+		b.textContent = option;
+		b.disabled = msg.revealed;
+		if (option === msg.selected) b.className = "selected";
+		b.onclick = () => send({type: "vote", points: option});
+		$("cards").appendChild(b);
+	}
+	$("players").innerHTML = "";
+	for (const p of msg.players) {
+		const li = document.createElement("li");
+		li.textContent = p.name + ": " + (msg.shared ? (p.voted ? p.points : "no vote") : (p.voted ? "✓" : "waiting..."));
+		$("players").appendChild(li);
+	}
+};
+</script>
+</body>
+</html>
+`
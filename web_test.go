@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHandleWebMessage tests the web gateway message handlers against the shared state
+func TestHandleWebMessage(t *testing.T) {
+	state.mu.Lock()
+	state.resetPlayers()
+	state.masterRevealed = false
+	state.mu.Unlock()
+
+	client := &webClient{}
+
+	if err := handleWebMessage(client, webMessage{Type: "vote", Points: "5"}); err == nil {
+		t.Errorf("vote before join: err = nil, want error")
+	}
+
+	if err := handleWebMessage(client, webMessage{Type: "join", Name: "alice"}); err != nil {
+		t.Fatalf("join: err = %v, want nil", err)
+	}
+
+	state.mu.RLock()
+	player, exists := state.players[playerKey("alice")]
+	state.mu.RUnlock()
+	if !exists {
+		t.Fatal("web player alice was not added to state")
+	}
+	if player.session != nil {
+		t.Errorf("web player session = %v, want nil", player.session)
+	}
+
+	other := &webClient{}
+	if err := handleWebMessage(other, webMessage{Type: "join", Name: "Alice"}); err == nil {
+		t.Errorf("join with taken name: err = nil, want error")
+	}
+
+	if err := handleWebMessage(client, webMessage{Type: "vote", Points: "42"}); err == nil {
+		t.Errorf("vote with invalid points: err = nil, want error")
+	}
+
+	if err := handleWebMessage(client, webMessage{Type: "vote", Points: "5"}); err != nil {
+		t.Errorf("vote: err = %v, want nil", err)
+	}
+
+	state.mu.Lock()
+	if !player.selected || player.points != "5" {
+		t.Errorf("after vote: points = %q (selected %v), want 5", player.points, player.selected)
+	}
+	state.masterRevealed = true
+	state.mu.Unlock()
+
+	if err := handleWebMessage(client, webMessage{Type: "vote", Points: "8"}); err == nil {
+		t.Errorf("vote after reveal: err = nil, want error")
+	}
+
+	client.leave()
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if _, exists := state.players[playerKey("alice")]; exists {
+		t.Errorf("web player alice still exists after leave")
+	}
+}
+
+// TestWebSnapshotHidesVotes tests that votes are only sent to browsers after reveal
+func TestWebSnapshotHidesVotes(t *testing.T) {
+	state.mu.Lock()
+	setTestPlayers(map[string]*playerState{
+		"bob": {name: "bob", points: "8", selected: true},
+	})
+	state.masterRevealed = false
+	state.playersRevealed = false
+	state.mu.Unlock()
+
+	client := &webClient{}
+
+	snapshot := client.snapshot()
+	if len(snapshot.Players) != 1 || !snapshot.Players[0].Voted || snapshot.Players[0].Points != "" {
+		t.Errorf("snapshot before reveal = %+v, want voted without points", snapshot.Players)
+	}
+
+	// A blind reveal closes voting but keeps the votes hidden until shared
+	state.mu.Lock()
+	state.masterRevealed = true
+	state.mu.Unlock()
+
+	snapshot = client.snapshot()
+	if !snapshot.Revealed || snapshot.Shared || snapshot.Players[0].Points != "" {
+		t.Errorf("snapshot after blind reveal = %+v, want closed without points", snapshot)
+	}
+
+	// The votes stay hidden during the suspense countdown of the reveal
+	state.mu.Lock()
+	state.playersRevealed = true
+	state.revealAt = time.Now().Add(time.Minute)
+	state.mu.Unlock()
+
+	snapshot = client.snapshot()
+	if snapshot.Shared || snapshot.Countdown == 0 || snapshot.Players[0].Points != "" {
+		t.Errorf("snapshot during the countdown = %+v, want a countdown without points", snapshot)
+	}
+
+	state.mu.Lock()
+	state.revealAt = time.Time{}
+	state.mu.Unlock()
+
+	snapshot = client.snapshot()
+	if !snapshot.Shared || len(snapshot.Players) != 1 || snapshot.Players[0].Points != "8" {
+		t.Errorf("snapshot after reveal = %+v, want points 8", snapshot.Players)
+	}
+}
+
+// TestWebPageNameLimit tests that the name input of the web page allows the
+// names the server accepts
+func TestWebPageNameLimit(t *testing.T) {
+	defer func(limit int) { options.NameLimit = limit }(options.NameLimit)
+	options.NameLimit = 42
+
+	rec := httptest.NewRecorder()
+	newWebHandler(10, 10).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := rec.Body.String(); !strings.Contains(body, `maxlength="42"`) {
+		t.Errorf("web page doesn't limit names to 42 characters:\n%s", body)
+	}
+}
+
+// maskFrame masks an unmasked frame of writeFrame, as a browser sends it.
+func maskFrame(frame []byte) []byte {
+	mask := [4]byte{0x12, 0x34, 0x56, 0x78}
+	headerLen := 2
+	switch frame[1] {
+	case 126:
+		headerLen += 2
+	case 127:
+		headerLen += 8
+	}
+	masked := append([]byte{}, frame[:headerLen]...)
+	masked[1] |= 0x80
+	masked = append(masked, mask[:]...)
+	for i, b := range frame[headerLen:] {
+		masked = append(masked, b^mask[i%4])
+	}
+	return masked
+}
+
+// TestWebsocketFrames tests that masked frames round-trip through the minimal
+// WebSocket codec, and unmasked client frames are refused
+func TestWebsocketFrames(t *testing.T) {
+	payloads := [][]byte{
+		[]byte(`{"type":"vote","points":"5"}`),
+		bytes.Repeat([]byte("x"), 300),
+	}
+
+	for _, payload := range payloads {
+		var buf bytes.Buffer
+		if err := writeFrame(&buf, wsOpText, payload); err != nil {
+			t.Fatalf("writeFrame() err = %v", err)
+		}
+		if _, _, err := readFrame(bytes.NewReader(buf.Bytes())); err == nil {
+			t.Errorf("readFrame() of an unmasked frame err = nil, want an error")
+		}
+
+		opcode, got, err := readFrame(bytes.NewReader(maskFrame(buf.Bytes())))
+		if err != nil {
+			t.Fatalf("readFrame() err = %v", err)
+		}
+		if opcode != wsOpText {
+			t.Errorf("readFrame() opcode = %d, want %d", opcode, wsOpText)
+		}
+		if !bytes.Equal(got, payload) {
+			t.Errorf("readFrame() payload = %q, want %q", got, payload)
+		}
+	}
+
+	// Sample handshake from RFC 6455
+	if got := websocketAccept("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("websocketAccept() = %s, want s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", got)
+	}
+}
+
+// TestWebGatewayAccess tests that WebSocket requests from other origins,
+// banned addresses, and beyond the connection limits are refused
+func TestWebGatewayAccess(t *testing.T) {
+	defer func(b *banList) { bans = b }(bans)
+	fingerprints, networks, err := parseBanList(strings.NewReader("10.0.0.0/8\n"))
+	if err != nil {
+		t.Fatalf("parseBanList() err = %v", err)
+	}
+	bans = &banList{fingerprints: fingerprints, networks: networks}
+
+	tests := []struct {
+		name           string
+		origin         string
+		remote         string
+		maxConnections int
+		wantStatus     int
+	}{
+		{"same origin", "http://example.com", "192.0.2.1:1234", 10, http.StatusBadRequest},
+		{"no origin", "", "192.0.2.1:1234", 10, http.StatusBadRequest},
+		{"other origin", "https://evil.example", "192.0.2.1:1234", 10, http.StatusForbidden},
+		{"banned address", "http://example.com", "10.1.2.3:1234", 10, http.StatusForbidden},
+		{"at capacity", "http://example.com", "192.0.2.1:1234", 0, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Requests that get through fail the upgrade, as they aren't one
+			r := httptest.NewRequest(http.MethodGet, "http://example.com/ws", nil)
+			r.RemoteAddr = tt.remote
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			newWebHandler(tt.maxConnections, 10).ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+	if n := connectionCount.Load(); n != 0 {
+		t.Errorf("connections = %d after the requests, want 0", n)
+	}
+}
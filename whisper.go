@@ -0,0 +1,51 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// whisperBuffer is how many whispers can wait for a player's program
+	// before new ones are dropped.
+	whisperBuffer = 4
+
+	// whisperDuration is how long a whisper stays visible in the player view.
+	whisperDuration = 5 * time.Second
+)
+
+// whisperMsg delivers a private message from the Scrum Master to a player's
+// running program.
+type whisperMsg string
+
+// waitForWhisper returns a command that waits for the next whisper on the
+// player's channel. The player view issues it again after each delivery.
+func waitForWhisper(whispers <-chan string) tea.Cmd {
+	if whispers == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		text, ok := <-whispers
+		if !ok {
+			return nil
+		}
+		return whisperMsg(text)
+	}
+}
+
+// whisper sends a private message to the player without blocking. It returns
+// false when the player has too many undelivered whispers or no program to
+// receive them. Callers must hold state.mu, as removing a player closes the
+// channel.
+func whisper(player *playerState, text string) bool {
+	if player.whispers == nil {
+		return false
+	}
+	select {
+	case player.whispers <- text:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestWhisperDelivery tests that a whisper reaches the player's program and view
+func TestWhisperDelivery(t *testing.T) {
+	state = newGameState()
+	model, cmd := initPlayerView("alice", nil)
+	if cmd == nil {
+		t.Fatal("initPlayerView() returned no command to wait for whispers")
+	}
+
+	state.mu.RLock()
+	delivered := whisper(state.players["alice"], "you're muted")
+	state.mu.RUnlock()
+
+	if !delivered {
+		t.Fatal("whisper() = false, want true")
+	}
+
+	// Run the command as the program would and deliver its message
+	msg := cmd()
+	if got, ok := msg.(whisperMsg); !ok || string(got) != "you're muted" {
+		t.Fatalf("waitForWhisper() message = %#v, want whisperMsg", msg)
+	}
+
+	model, cmd = model.Update(msg)
+	if cmd == nil {
+		t.Errorf("Update(whisperMsg) returned no command to wait for the next whisper")
+	}
+	if view := model.View(); !strings.Contains(view, "you're muted") {
+		t.Errorf("View() missing whisper\nGot: %s", view)
+	}
+
+	// The whisper is dismissed after a few seconds
+	p := model.(playerView)
+	p.whisperUntil = time.Now().Add(-time.Second)
+	if view := p.View(); strings.Contains(view, "you're muted") {
+		t.Errorf("View() still shows expired whisper\nGot: %s", view)
+	}
+}
+
+// TestWhisperBufferFull tests that whispers never block the master
+func TestWhisperBufferFull(t *testing.T) {
+	player := &playerState{whispers: make(chan string, whisperBuffer)}
+
+	for i := 0; i < whisperBuffer; i++ {
+		if !whisper(player, "hello") {
+			t.Fatalf("whisper() %d = false, want true", i)
+		}
+	}
+	if whisper(player, "dropped") {
+		t.Errorf("whisper() on full buffer = true, want false")
+	}
+}
+
+// TestMasterWhisper tests whispering from the master view to the selected player
+func TestMasterWhisper(t *testing.T) {
+	state = newGameState()
+	addPlayer("alice", nil)
+	addPlayer("bob", nil)
+
+	var model tea.Model = newMasterView()
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hi bob")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if view := model.View(); !strings.Contains(view, "Whispered to bob") {
+		t.Errorf("View() missing whisper confirmation\nGot: %s", view)
+	}
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	select {
+	case got := <-state.players["bob"].whispers:
+		if got != "hi bob" {
+			t.Errorf("bob received %q, want %q", got, "hi bob")
+		}
+	default:
+		t.Errorf("bob received no whisper")
+	}
+	if len(state.players["alice"].whispers) != 0 {
+		t.Errorf("alice received a whisper meant for bob")
+	}
+}